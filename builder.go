@@ -0,0 +1,164 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Builder constructs a [Zone] record by record, e.g. in tests:
+//
+//	z, err := zone.NewBuilder("example.com.").
+//		TTL(3600).
+//		SOA("ns1", "hostmaster", 1, 7200, 3600, 1209600, 300).
+//		NS("ns1", "ns2").
+//		A("www", "192.0.2.1").
+//		MX(10, "mail").
+//		TXT("@", "v=spf1 -all").
+//		Build()
+//
+// Names, including those in values, are qualified with the origin, and `@`
+// is the origin itself. Every record is checked with
+// [ResourceRecord.Validate], and added with [Zone.Add], which enforces the
+// invariants of a zone. Errors do not stop the builder: they are collected
+// across all calls and returned by [Builder.Build].
+type Builder struct {
+	origin string
+	ttl    int
+	zone   *Zone
+	errs   []error
+}
+
+// NewBuilder creates a builder of a zone with the given origin. Names are
+// kept as given when the origin is empty.
+func NewBuilder(origin string) *Builder {
+	if origin != "" {
+		origin = fqdn(origin)
+	}
+	return &Builder{
+		origin: origin,
+		ttl:    defaultTtl,
+		zone: &Zone{
+			Records: make([]ResourceRecord, 0),
+		},
+	}
+}
+
+// TTL sets the TTL of the records that are added after it. The default is
+// `86_400`.
+func (b *Builder) TTL(ttl int) *Builder {
+	if ttl < 0 {
+		b.errs = append(b.errs, fmt.Errorf("ttl must not be negative: %d", ttl))
+		return b
+	}
+	b.ttl = ttl
+	return b
+}
+
+// Record adds a record of any type at name, with values in presentation
+// format, e.g. `Record("www", "HTTPS", "1", ".", "alpn=h2")`.
+func (b *Builder) Record(name string, recordType string, values ...string) *Builder {
+	rr := ResourceRecord{
+		Name:   qualifyName(name, b.origin),
+		Class:  defaultClass,
+		Type:   recordType,
+		TTL:    b.ttl,
+		HasTTL: true,
+		Values: values,
+	}
+	rr = qualifyRdata(rr, b.origin)
+
+	err := rr.Validate()
+	if err == nil {
+		err = b.zone.Add(rr)
+	} else {
+		err = &RecordError{Record: rr, Err: err}
+	}
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+	return b
+}
+
+// SOA adds the SOA record of the zone at the origin.
+func (b *Builder) SOA(mname string, rname string, serial uint32, refresh int, retry int, expire int, minimum int) *Builder {
+	return b.Record("@", "SOA", mname, rname,
+		strconv.FormatUint(uint64(serial), 10),
+		strconv.Itoa(refresh),
+		strconv.Itoa(retry),
+		strconv.Itoa(expire),
+		strconv.Itoa(minimum),
+	)
+}
+
+// NS adds an NS record at the origin for every host.
+func (b *Builder) NS(hosts ...string) *Builder {
+	for _, host := range hosts {
+		b.Record("@", "NS", host)
+	}
+	return b
+}
+
+// A adds an A record at name for every address.
+func (b *Builder) A(name string, addresses ...string) *Builder {
+	for _, address := range addresses {
+		b.Record(name, "A", address)
+	}
+	return b
+}
+
+// AAAA adds an AAAA record at name for every address.
+func (b *Builder) AAAA(name string, addresses ...string) *Builder {
+	for _, address := range addresses {
+		b.Record(name, "AAAA", address)
+	}
+	return b
+}
+
+// CNAME adds a CNAME record at name.
+func (b *Builder) CNAME(name string, target string) *Builder {
+	return b.Record(name, "CNAME", target)
+}
+
+// MX adds an MX record at the origin.
+func (b *Builder) MX(preference int, exchange string) *Builder {
+	return b.Record("@", "MX", strconv.Itoa(preference), exchange)
+}
+
+// PTR adds a PTR record at name.
+func (b *Builder) PTR(name string, target string) *Builder {
+	return b.Record(name, "PTR", target)
+}
+
+// SRV adds an SRV record at name, e.g. `_sip._tcp`.
+func (b *Builder) SRV(name string, priority int, weight int, port int, target string) *Builder {
+	return b.Record(name, "SRV", strconv.Itoa(priority), strconv.Itoa(weight), strconv.Itoa(port), target)
+}
+
+// TXT adds a TXT record at name. The text is given without quotes, and is
+// split into strings of 255 bytes. Several texts are concatenated, as DNS
+// clients do with the strings of a record.
+func (b *Builder) TXT(name string, texts ...string) *Builder {
+	values := make([]string, 0)
+	for _, text := range texts {
+		for _, chunk := range splitCharacterString(text) {
+			values = append(values, quoteCharacterString(chunk))
+		}
+	}
+	return b.Record(name, "TXT", values...)
+}
+
+// CAA adds a CAA record at name, e.g. `CAA("@", 0, "issue",
+// "letsencrypt.org")`. The value is given without quotes.
+func (b *Builder) CAA(name string, flags int, tag string, value string) *Builder {
+	return b.Record(name, "CAA", strconv.Itoa(flags), tag, quoteCharacterString(value))
+}
+
+// Build returns the zone. When any call failed, the errors of every call
+// are returned joined, and no zone is returned.
+func (b *Builder) Build() (*Zone, error) {
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	return b.zone.Clone(), nil
+}
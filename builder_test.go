@@ -0,0 +1,67 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_Builder(t *testing.T) {
+	z, err := NewBuilder("example.com").
+		TTL(3600).
+		SOA("ns1", "hostmaster", 1, 7200, 3600, 1209600, 300).
+		NS("ns1", "ns2.example.net.").
+		A("www", "192.0.2.1", "192.0.2.2").
+		AAAA("www", "2001:db8::1").
+		MX(10, "mail").
+		TTL(300).
+		TXT("@", "v=spf1 -all").
+		TXT("long", strings.Repeat("a", 300)).
+		CNAME("ftp", "www").
+		SRV("_sip._tcp", 10, 5, 5060, "sip").
+		PTR("1.2.0.192.in-addr.arpa.", "www").
+		CAA("@", 0, "issue", "letsencrypt.org").
+		Record("svc", "HTTPS", "1", ".", "alpn=h2").
+		Build()
+	require.Nil(t, err)
+	assert.Equal(t, `example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+example.com. 3600 IN NS ns1.example.com.
+example.com. 3600 IN NS ns2.example.net.
+www.example.com. 3600 IN A 192.0.2.1
+www.example.com. 3600 IN A 192.0.2.2
+www.example.com. 3600 IN AAAA 2001:db8::1
+example.com. 3600 IN MX 10 mail.example.com.
+example.com. 300 IN TXT "v=spf1 -all"
+long.example.com. 300 IN TXT "`+strings.Repeat("a", 255)+`" "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+ftp.example.com. 300 IN CNAME www.example.com.
+_sip._tcp.example.com. 300 IN SRV 10 5 5060 sip.example.com.
+1.2.0.192.in-addr.arpa. 300 IN PTR www.example.com.
+example.com. 300 IN CAA 0 issue "letsencrypt.org"
+svc.example.com. 300 IN HTTPS 1 . alpn=h2
+`, z.String())
+}
+
+func Test_Builder_Errors(t *testing.T) {
+	_, err := NewBuilder("example.com.").
+		TTL(-1).
+		A("www", "192.0.2").
+		A("ftp", "192.0.2.1").
+		CNAME("ftp", "www").
+		MX(70000, "mail").
+		Record("x", "NOPE", "1").
+		SOA("ns1", "hostmaster", 1, 2, 3, 4, 5).
+		SOA("ns2", "hostmaster", 1, 2, 3, 4, 5).
+		Build()
+	require.NotNil(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, "ttl must not be negative: -1", lines[0])
+	assert.Contains(t, lines[1], "www.example.com. A: ")
+	assert.Contains(t, lines[2], "ftp.example.com. CNAME: CNAME record must be the only record at its name")
+	assert.Contains(t, lines[3], "example.com. MX: ")
+	assert.Contains(t, lines[4], "x.example.com. NOPE: unknown type NOPE")
+	assert.Contains(t, lines[5], "zone already has a SOA record")
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	assert.ErrorIs(t, err, ErrZoneInvariant)
+}
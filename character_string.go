@@ -0,0 +1,110 @@
+package zone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCharacterStringLength is the maximum number of bytes in a single
+// <character-string>, per RFC 1035 §3.3.
+const maxCharacterStringLength = 255
+
+// parseCharacterString decodes a <character-string> token from presentation
+// format as described in RFC 1035 §5.1. Surrounding quotes are removed, and
+// `\X` and `\DDD` escapes are resolved.
+//
+//	result, _ := parseCharacterString(`"v=DKIM1\; k=rsa"`)
+//	fmt.Println(result) // `v=DKIM1; k=rsa`
+func parseCharacterString(token string) (string, error) {
+	if len(token) >= 2 && token[0] == quoteByte && token[len(token)-1] == quoteByte {
+		token = token[1 : len(token)-1]
+	}
+
+	str := strings.Builder{}
+	for i := 0; i < len(token); i++ {
+		if token[i] != escapeByte {
+			str.WriteByte(token[i])
+			continue
+		}
+
+		i += 1
+		if i == len(token) {
+			return "", fmt.Errorf("parse character string %s: dangling escape: %w", token, ErrInvalidRdata)
+		}
+		if isDigitByte(token[i]) == false {
+			str.WriteByte(token[i])
+			continue
+		}
+		if i+2 >= len(token) || isDigitByte(token[i+1]) == false || isDigitByte(token[i+2]) == false {
+			return "", fmt.Errorf("parse character string %s: incomplete \\DDD escape: %w", token, ErrInvalidRdata)
+		}
+		value := int(token[i]-'0')*100 + int(token[i+1]-'0')*10 + int(token[i+2]-'0')
+		if value > 255 {
+			return "", fmt.Errorf("parse character string %s: escape out of range: %w", token, ErrInvalidRdata)
+		}
+		str.WriteByte(byte(value))
+		i += 2
+	}
+	return str.String(), nil
+}
+
+// parseCharacterStrings decodes every token in a set of record values with
+// [parseCharacterString].
+func parseCharacterStrings(values []string) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		str, err := parseCharacterString(v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
+// quoteCharacterString encodes a string as a quoted <character-string> in
+// presentation format. Quotes and backslashes are escaped, and bytes outside
+// of printable ASCII are written as `\DDD` escapes.
+func quoteCharacterString(value string) string {
+	str := strings.Builder{}
+	str.WriteByte(quoteByte)
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case b == quoteByte || b == escapeByte:
+			str.WriteByte(escapeByte)
+			str.WriteByte(b)
+		case b < 0x20 || b > 0x7e:
+			str.WriteString(fmt.Sprintf("\\%03d", b))
+		default:
+			str.WriteByte(b)
+		}
+	}
+	str.WriteByte(quoteByte)
+	return str.String()
+}
+
+// splitCharacterString divides a decoded string into chunks that each fit
+// within a single <character-string>.
+func splitCharacterString(value string) []string {
+	result := make([]string, 0, len(value)/maxCharacterStringLength+1)
+	for len(value) > maxCharacterStringLength {
+		result = append(result, value[:maxCharacterStringLength])
+		value = value[maxCharacterStringLength:]
+	}
+	return append(result, value)
+}
+
+// isTextType determines if the values of a record type are a sequence of
+// <character-string> tokens.
+func isTextType(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "TXT", "SPF":
+		return true
+	}
+	return false
+}
+
+func isDigitByte(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,41 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_parseCharacterString(t *testing.T) {
+	tests := [][]string{
+		{`"foo bar"`, "foo bar"},
+		{`foo`, "foo"},
+		{`"v=DKIM1\; k=rsa"`, "v=DKIM1; k=rsa"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"tab\009here"`, "tab\there"},
+		{`""`, ""},
+	}
+
+	for _, test := range tests {
+		found, err := parseCharacterString(test[0])
+		assert.Nil(t, err)
+		assert.Equal(t, test[1], found)
+	}
+
+	for _, test := range []string{`"foo\"`, `foo\1`, `\256`} {
+		_, err := parseCharacterString(test)
+		assert.ErrorIs(t, err, ErrInvalidRdata, test)
+	}
+}
+
+func Test_quoteCharacterString(t *testing.T) {
+	tests := [][]string{
+		{"foo bar", `"foo bar"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`back\slash`, `"back\\slash"`},
+		{"tab\there", `"tab\009here"`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test[1], quoteCharacterString(test[0]))
+	}
+}
@@ -0,0 +1,459 @@
+// Command gozone reads, checks, and converts DNS zone files.
+//
+// Usage:
+//
+//	gozone parse [options] [file ...]
+//	gozone check [options] [--lint=false] [--disable rule,...] [--policy file] [file ...]
+//	gozone fmt [options] [-w] [file ...]
+//	gozone diff [options] old new
+//	gozone convert [options] [--from format] --to format [file]
+//	gozone query [options] name type [file]
+//	gozone select [options] expression [file ...]
+//
+// Files are read from stdin when none are given, or when a file is `-`.
+// Every command accepts the options of the zone file parser:
+//
+//	--default-ttl seconds   TTL of records when the file has no $TTL
+//	--prefer-soa-min-ttl    use the SOA minimum as the TTL of every record
+//	--origin name           origin of relative names until an $ORIGIN
+//
+// Besides validating every record, check reports the findings of the lint
+// rules of the zone package, which can be suppressed with `; lint:ignore`
+// comments, and the violations of the YAML policy given with --policy.
+// Findings of the info severity do not fail the check.
+//
+// The exit status is 0 on success, 1 when check finds problems, diff finds
+// differences, or query and select find no records, and 2 on any other
+// error.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jsumners/go-zone"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	exitOK       = 0
+	exitProblems = 1
+	exitError    = 2
+)
+
+const usage = `usage: gozone <command> [options] [arguments]
+
+commands:
+  parse    print the records of zone files, one per line
+  check    validate and lint the records of zone files
+  fmt      print zone files in the canonical format of named-compilezone
+  diff     print the record set changes between two zone files
+  convert  convert a zone between formats
+  query    print the records with a name and type
+  select   print the records that match a selector expression
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command holds the state shared by all commands.
+type command struct {
+	name   string
+	flags  *flag.FlagSet
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	comments        bool
+	defaultTtl      int
+	origin          string
+	preferSoaMinTtl bool
+}
+
+// run executes the command line given by args, and returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return exitError
+		}
+		return exitOK
+	}
+
+	cmd := &command{
+		name:   args[0],
+		flags:  flag.NewFlagSet("gozone "+args[0], flag.ContinueOnError),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	cmd.flags.SetOutput(stderr)
+	cmd.flags.IntVar(&cmd.defaultTtl, "default-ttl", 86400, "TTL of records when the file has no $TTL directive")
+	cmd.flags.StringVar(&cmd.origin, "origin", "", "origin of relative names until an $ORIGIN directive")
+	cmd.flags.BoolVar(&cmd.preferSoaMinTtl, "prefer-soa-min-ttl", false, "use the SOA minimum as the TTL of every record")
+
+	var err error
+	status := exitOK
+	switch cmd.name {
+	case "parse":
+		status, err = cmd.parse(args[1:])
+	case "check":
+		status, err = cmd.check(args[1:])
+	case "fmt":
+		status, err = cmd.format(args[1:])
+	case "diff":
+		status, err = cmd.diff(args[1:])
+	case "convert":
+		status, err = cmd.convert(args[1:])
+	case "query":
+		status, err = cmd.query(args[1:])
+	case "select":
+		status, err = cmd.selectRecords(args[1:])
+	default:
+		fmt.Fprintf(stderr, "gozone: unknown command %q\n%s", cmd.name, usage)
+		return exitError
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gozone %s: %s\n", cmd.name, err)
+		return exitError
+	}
+	return status
+}
+
+// parserOptions lists the zone parser options given on the command line.
+func (cmd *command) parserOptions(fileName string) []zone.Option {
+	opts := []zone.Option{
+		zone.WithDefaultTtl(cmd.defaultTtl),
+		zone.WithPreferSoaMinTtl(cmd.preferSoaMinTtl),
+		zone.WithComments(cmd.comments),
+	}
+	if cmd.origin != "" {
+		opts = append(opts, zone.WithOrigin(cmd.origin))
+	}
+	if fileName != "-" {
+		opts = append(opts, zone.WithFileName(fileName))
+	}
+	return opts
+}
+
+// parser creates a zone parser with the options given on the command line.
+func (cmd *command) parser(fileName string) (*zone.ZoneParser, error) {
+	return zone.NewZoneParser(cmd.parserOptions(fileName)...)
+}
+
+// open opens a file, where `-` is stdin.
+func (cmd *command) open(fileName string) (io.ReadCloser, error) {
+	if fileName == "-" {
+		return io.NopCloser(cmd.stdin), nil
+	}
+	return os.Open(fileName)
+}
+
+// read parses a zone file.
+func (cmd *command) read(fileName string) (*zone.Zone, error) {
+	zp, err := cmd.parser(fileName)
+	if err != nil {
+		return nil, err
+	}
+	file, err := cmd.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return zp.Parse(file)
+}
+
+// files lists the files named by the arguments, or stdin when there are
+// none.
+func files(args []string) []string {
+	if len(args) == 0 {
+		return []string{"-"}
+	}
+	return args
+}
+
+func (cmd *command) parse(args []string) (int, error) {
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	for _, fileName := range files(cmd.flags.Args()) {
+		z, err := cmd.read(fileName)
+		if err != nil {
+			return exitError, err
+		}
+		_, err = io.WriteString(cmd.stdout, z.String())
+		if err != nil {
+			return exitError, err
+		}
+	}
+	return exitOK, nil
+}
+
+func (cmd *command) check(args []string) (int, error) {
+	lint := cmd.flags.Bool("lint", true, "report the findings of the lint rules")
+	disable := cmd.flags.String("disable", "", "comma separated lint rules to disable: "+strings.Join(zone.LintRules(), ", "))
+	policyFile := cmd.flags.String("policy", "", "YAML file of policy rules that the records must follow")
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	var policy *zone.Policy
+	if *policyFile != "" {
+		policy, err = readPolicy(*policyFile)
+		if err != nil {
+			return exitError, err
+		}
+	}
+	lintOpts := make([]zone.LintOption, 0)
+	if cmd.origin != "" {
+		lintOpts = append(lintOpts, zone.WithLintOrigin(cmd.origin))
+	}
+	for _, rule := range strings.Split(*disable, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			lintOpts = append(lintOpts, zone.WithLintSeverity(rule, zone.SeverityOff))
+		}
+	}
+	cmd.comments = true
+
+	status := exitOK
+	for _, fileName := range files(cmd.flags.Args()) {
+		z, err := cmd.read(fileName)
+		if err != nil {
+			return exitError, err
+		}
+		records := z.AllRecords()
+		for _, rr := range records {
+			err := rr.Validate()
+			if err != nil {
+				fmt.Fprintln(cmd.stdout, &zone.RecordError{Record: rr, Err: err})
+				status = exitProblems
+			}
+		}
+		findings := make([]zone.Finding, 0)
+		if *lint == true {
+			lintFindings, err := zone.Lint(z, lintOpts...)
+			if err != nil {
+				return exitError, err
+			}
+			findings = append(findings, lintFindings...)
+		}
+		if policy != nil {
+			violations, err := policy.Evaluate(z)
+			if err != nil {
+				return exitError, err
+			}
+			findings = append(findings, violations...)
+		}
+		for _, finding := range findings {
+			fmt.Fprintln(cmd.stdout, finding)
+			if finding.Severity > zone.SeverityInfo {
+				status = exitProblems
+			}
+		}
+	}
+	return status, nil
+}
+
+// readPolicy reads a policy file.
+func readPolicy(fileName string) (*zone.Policy, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return zone.ParsePolicy(file)
+}
+
+func (cmd *command) format(args []string) (int, error) {
+	write := cmd.flags.Bool("w", false, "write the result to the file instead of stdout")
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	for _, fileName := range files(cmd.flags.Args()) {
+		z, err := cmd.read(fileName)
+		if err != nil {
+			return exitError, err
+		}
+		buf := bytes.Buffer{}
+		err = zone.WriteCompileZone(&buf, z, cmd.zoneOrigin(z))
+		if err != nil {
+			return exitError, err
+		}
+		if *write == true && fileName != "-" {
+			err = os.WriteFile(fileName, buf.Bytes(), 0o644)
+		} else {
+			_, err = buf.WriteTo(cmd.stdout)
+		}
+		if err != nil {
+			return exitError, err
+		}
+	}
+	return exitOK, nil
+}
+
+// zoneOrigin determines the origin of a zone: the origin given on the
+// command line, else the owner of an absolute SOA record. It is empty when
+// neither is known.
+func (cmd *command) zoneOrigin(z *zone.Zone) string {
+	if cmd.origin != "" {
+		return cmd.origin
+	}
+	if strings.HasSuffix(z.SOA.Name, ".") {
+		return z.SOA.Name
+	}
+	return ""
+}
+
+func (cmd *command) diff(args []string) (int, error) {
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	if cmd.flags.NArg() != 2 {
+		return exitError, errors.New("expected an old file and a new file")
+	}
+	oldZone, err := cmd.read(cmd.flags.Arg(0))
+	if err != nil {
+		return exitError, err
+	}
+	newZone, err := cmd.read(cmd.flags.Arg(1))
+	if err != nil {
+		return exitError, err
+	}
+
+	changes := zone.Diff(oldZone, newZone)
+	for _, change := range changes {
+		for _, rr := range change.Old.Records {
+			fmt.Fprint(cmd.stdout, "- "+rr.String())
+		}
+		for _, rr := range change.New.Records {
+			fmt.Fprint(cmd.stdout, "+ "+rr.String())
+		}
+	}
+	if len(changes) > 0 {
+		return exitProblems, nil
+	}
+	return exitOK, nil
+}
+
+func (cmd *command) convert(args []string) (int, error) {
+	from := cmd.flags.String("from", "bind", "format to read: "+strings.Join(zone.DecoderNames(), ", "))
+	to := cmd.flags.String("to", "", "format to write: "+strings.Join(zone.EncoderNames(), ", "))
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	if cmd.flags.NArg() > 1 {
+		return exitError, errors.New("expected at most one file")
+	}
+	encoder, err := zone.LookupEncoder(*to)
+	if err != nil {
+		return exitError, err
+	}
+	fileName := files(cmd.flags.Args())[0]
+
+	// Zone files are read with the parser options, other formats with
+	// their registered decoders.
+	var decoder zone.Decoder
+	if strings.EqualFold(*from, "bind") {
+		decoder = zone.NewZoneFileDecoder(cmd.parserOptions(fileName)...)
+	} else {
+		decoder, err = zone.LookupDecoder(*from)
+		if err != nil {
+			return exitError, err
+		}
+	}
+	file, err := cmd.open(fileName)
+	if err != nil {
+		return exitError, err
+	}
+	defer file.Close()
+	z, err := decoder.Decode(file, cmd.origin)
+	if err != nil {
+		return exitError, err
+	}
+
+	if unsupported := zone.UnsupportedTypes(z, encoder.Supports); len(unsupported) > 0 {
+		fmt.Fprintf(cmd.stderr, "gozone convert: %s cannot represent %s records\n", *to, strings.Join(unsupported, ", "))
+	}
+	err = encoder.Encode(cmd.stdout, z, cmd.zoneOrigin(z))
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+func (cmd *command) query(args []string) (int, error) {
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	if cmd.flags.NArg() < 2 || cmd.flags.NArg() > 3 {
+		return exitError, errors.New("expected a name, a type, and at most one file")
+	}
+	name, recordType := cmd.flags.Arg(0), cmd.flags.Arg(1)
+	if name == "@" && cmd.origin != "" {
+		name = strings.TrimSuffix(cmd.origin, ".") + "."
+	} else if cmd.origin != "" && strings.HasSuffix(name, ".") == false {
+		name = strings.TrimSuffix(name+"."+cmd.origin, ".") + "."
+	}
+	z, err := cmd.read(files(cmd.flags.Args()[2:])[0])
+	if err != nil {
+		return exitError, err
+	}
+
+	records := z.AllRecords()
+	status := exitProblems
+	for _, rr := range records {
+		if strings.EqualFold(rr.Name, name) == false {
+			continue
+		}
+		if strings.EqualFold(recordType, "ANY") == false && strings.EqualFold(rr.Type, recordType) == false {
+			continue
+		}
+		fmt.Fprint(cmd.stdout, rr.String())
+		status = exitOK
+	}
+	return status, nil
+}
+
+// selectRecords prints the records that match a selector expression, e.g.
+// `type=A,AAAA ttl<300`.
+func (cmd *command) selectRecords(args []string) (int, error) {
+	err := cmd.flags.Parse(args)
+	if err != nil {
+		return exitError, err
+	}
+	if cmd.flags.NArg() < 1 {
+		return exitError, errors.New("expected a selector expression")
+	}
+	selector, err := zone.ParseSelector(cmd.flags.Arg(0))
+	if err != nil {
+		return exitError, err
+	}
+
+	status := exitProblems
+	for _, fileName := range files(cmd.flags.Args()[1:]) {
+		z, err := cmd.read(fileName)
+		if err != nil {
+			return exitError, err
+		}
+		records := z.AllRecords()
+		for _, rr := range records {
+			if selector.Matches(rr) {
+				fmt.Fprint(cmd.stdout, rr.String())
+				status = exitOK
+			}
+		}
+	}
+	return status, nil
+}
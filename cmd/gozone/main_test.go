@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleZone = `$TTL 300
+@ IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ IN NS ns1
+www IN A 192.0.2.1
+mail IN MX 10 www
+`
+
+// execute runs a command line with the given stdin, and returns the exit
+// status, stdout, and stderr.
+func execute(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	status := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func writeFile(t *testing.T, name string, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(data), 0o644))
+	return path
+}
+
+func Test_run(t *testing.T) {
+	status, _, stderr := execute(t, "")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "usage: gozone")
+
+	status, _, stderr = execute(t, "", "frobnicate")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, `unknown command "frobnicate"`)
+
+	status, _, stderr = execute(t, "", "parse", "/does/not/exist")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "gozone parse: open /does/not/exist")
+}
+
+func Test_parse(t *testing.T) {
+	status, stdout, _ := execute(t, sampleZone, "parse", "--origin", "example.com", "--default-ttl", "60")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, `example.com. 300 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+example.com. 300 IN NS ns1
+www.example.com. 300 IN A 192.0.2.1
+mail.example.com. 300 IN MX 10 www
+`, stdout)
+
+	path := writeFile(t, "db.example", "www IN A 192.0.2.1\n")
+	status, stdout, _ = execute(t, "", "parse", "--default-ttl", "60", path)
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "www 60 IN A 192.0.2.1\n", stdout)
+}
+
+func Test_check(t *testing.T) {
+	status, stdout, _ := execute(t, sampleZone, "check")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "", stdout)
+
+	path := writeFile(t, "db.example", sampleZone+"bad IN A 192.0.2\nworse IN MX mail\n")
+	status, stdout, _ = execute(t, "", "check", path)
+	assert.Equal(t, exitProblems, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], path+":6: bad A: address"), lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], path+":7: worse MX: expected 2 values"), lines[1])
+
+	// Info findings are reported without failing the check.
+	status, stdout, _ = execute(t, sampleZone, "check", "--origin", "example.com")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "3: info: example.com. NS: ttl 300 differs from the ttl 172800 of the delegation (ns-ttl)\n", stdout)
+
+	path = writeFile(t, "db.example", sampleZone+"my_host IN A 192.0.2.2\nlegacy 60 IN A 192.0.2.3 ; lint:ignore ttl-range\n")
+	status, stdout, _ = execute(t, "", "check", "--disable", "ns-ttl", path)
+	assert.Equal(t, exitProblems, status)
+	assert.Equal(t, path+":6: warning: my_host A: owner name has the invalid label \"my_host\" (hostname)\n", stdout)
+
+	status, stdout, _ = execute(t, "", "check", "--lint=false", path)
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "", stdout)
+
+	status, _, stderr := execute(t, sampleZone, "check", "--disable", "nope")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "unknown lint rule: nope")
+
+	policy := writeFile(t, "policy.yaml", "rules:\n  - {id: mx, select: {type: MX}, assert: {field: exchange, in: [mail]}}\n")
+	status, stdout, _ = execute(t, sampleZone, "check", "--lint=false", "--policy", policy)
+	assert.Equal(t, exitProblems, status)
+	assert.Equal(t, "5: error: mail MX: exchange www is not one of mail (mx)\n", stdout)
+}
+
+func Test_format(t *testing.T) {
+	status, stdout, _ := execute(t, sampleZone, "fmt", "--origin", "example.com.")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "example.com.\t\t\t\t      300 IN SOA\tns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300\n"+
+		"example.com.\t\t\t\t      300 IN NS\t\tns1.example.com.\n"+
+		"mail.example.com.\t\t\t      300 IN MX\t\t10 www.example.com.\n"+
+		"www.example.com.\t\t\t      300 IN A\t\t192.0.2.1\n", stdout)
+
+	path := writeFile(t, "db.example", "$ORIGIN example.com.\n"+sampleZone)
+	status, stdout, _ = execute(t, "", "fmt", "-w", path)
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "", stdout)
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "example.com.\t\t\t\t      300 IN SOA\t"))
+}
+
+func Test_diff(t *testing.T) {
+	oldPath := writeFile(t, "old", sampleZone)
+	newPath := writeFile(t, "new", strings.Replace(sampleZone, "192.0.2.1", "192.0.2.2", 1)+"ftp IN CNAME www\n")
+
+	status, stdout, _ := execute(t, "", "diff", oldPath, newPath)
+	assert.Equal(t, exitProblems, status)
+	assert.Equal(t, `- www 300 IN A 192.0.2.1
++ www 300 IN A 192.0.2.2
++ ftp 300 IN CNAME www
+`, stdout)
+
+	status, stdout, _ = execute(t, sampleZone, "diff", oldPath, "-")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "", stdout)
+
+	status, _, stderr := execute(t, "", "diff", oldPath)
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "expected an old file and a new file")
+}
+
+func Test_convert(t *testing.T) {
+	status, stdout, stderr := execute(t, sampleZone, "convert", "--origin", "example.com", "--to", "hosts")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "192.0.2.1\twww.example.com\n", stdout)
+	assert.Equal(t, "gozone convert: hosts cannot represent MX, NS, SOA records\n", stderr)
+
+	status, stdout, _ = execute(t, "192.0.2.1 www.example.com\n", "convert", "--from", "hosts", "--to", "csv")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "name,ttl,class,type,value\nwww.example.com.,86400,IN,A,192.0.2.1\n", stdout)
+
+	// Relative names in values are qualified with the origin of their line.
+	input := "$ORIGIN test.\n@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300\n$ORIGIN sub.test.\nc 300 IN CNAME d\n"
+	status, stdout, _ = execute(t, input, "convert", "--to", "octodns")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "c.sub:\n  type: CNAME\n  ttl: 300\n  value: d.sub.test.\n", stdout)
+
+	status, _, stderr = execute(t, sampleZone, "convert", "--to", "yaml")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "unknown format")
+}
+
+func Test_query(t *testing.T) {
+	status, stdout, _ := execute(t, sampleZone, "query", "--origin", "example.com", "www", "a")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "www.example.com. 300 IN A 192.0.2.1\n", stdout)
+
+	status, stdout, _ = execute(t, sampleZone, "query", "--origin", "example.com", "@", "ANY")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "example.com. 300 IN SOA ns1 hostmaster 1 7200 3600 1209600 300\nexample.com. 300 IN NS ns1\n", stdout)
+
+	status, stdout, _ = execute(t, sampleZone, "query", "ftp", "A")
+	assert.Equal(t, exitProblems, status)
+	assert.Equal(t, "", stdout)
+}
+
+func Test_selectRecords(t *testing.T) {
+	status, stdout, _ := execute(t, sampleZone, "select", "--origin", "example.com", "type=A,MX name$=example.com.")
+	assert.Equal(t, exitOK, status)
+	assert.Equal(t, "www.example.com. 300 IN A 192.0.2.1\nmail.example.com. 300 IN MX 10 www\n", stdout)
+
+	status, stdout, _ = execute(t, sampleZone, "select", "ttl<300")
+	assert.Equal(t, exitProblems, status)
+	assert.Equal(t, "", stdout)
+
+	status, _, stderr := execute(t, sampleZone, "select", "ttl<")
+	assert.Equal(t, exitError, status)
+	assert.Contains(t, stderr, "gozone select: parse selector")
+}
@@ -0,0 +1,333 @@
+package zone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The columns, and tab width, of the `full` style of named-compilezone.
+const (
+	compileZoneTtlColumn   = 46
+	compileZoneClassColumn = 46
+	compileZoneTypeColumn  = 46
+	compileZoneRdataColumn = 64
+	compileZoneTabWidth    = 8
+)
+
+// compileZoneWordLength is the number of characters after which BIND breaks
+// base64 and hexadecimal data with a space.
+const compileZoneWordLength = 56
+
+// compileZoneSet is a record set in the form that named-compilezone writes
+// it.
+type compileZoneSet struct {
+	name       string
+	class      string
+	classCode  uint16
+	recordType string
+	typeCode   uint16
+	ttl        int
+	rdata      []compileZoneRdata
+
+	// covers is the type code covered by an RRSIG set.
+	covers uint16
+}
+
+type compileZoneRdata struct {
+	text string
+
+	// key orders the records of a set. It is the canonical wire format of
+	// the RDATA, as described in RFC 4034 §6.2, or the presentation format
+	// when the wire format cannot be determined.
+	key []byte
+}
+
+// WriteCompileZone writes a zone in the format of `named-compilezone -o -
+// <origin>`, so that the records this package reads from a file can be
+// compared with those BIND reads from it. Relative names are qualified with
+// origin.
+//
+// The format follows the layout and normalization rules of BIND described
+// below. It has not been verified against the output of named-compilezone
+// byte for byte, so differences in white space, or in how long data is
+// broken, are possible.
+//
+// Every line holds an absolute owner name, the TTL, and the class and type
+// in upper case, aligned in BIND's `full` style. Names and values are
+// written in BIND's presentation format, e.g. numbers and addresses are
+// normalized, text strings are always quoted, values in the RFC 3597
+// generic form are decoded when the type is known, and base64 and
+// hexadecimal data is broken into words of 56 characters. Values of types
+// whose wire format is not understood, e.g. NSEC or SVCB, are written as
+// given.
+//
+// Record sets are sorted by owner name in the canonical order of RFC 4034
+// §6.1. The SOA set is written first, then the NS set, then the others by
+// type code, each followed by the RRSIG records that cover it. Duplicate
+// records are dropped, and the records of a set are sorted by their RDATA.
+// Like BIND, every record of a set is given the TTL of the first.
+//
+// Records whose values BIND would reject are reported as [RecordError]
+// values in the returned error, and nothing is written.
+func WriteCompileZone(w io.Writer, z *Zone, origin string) error {
+	origin = fqdn(origin)
+	records := z.AllRecords()
+
+	var errs []error
+	sets := make([]*compileZoneSet, 0)
+	index := make(map[string]*compileZoneSet)
+	owners := make(map[string]string)
+	seen := make(map[string]bool)
+	for _, rr := range records {
+		rr = qualifyRdata(rr, origin)
+		rr.Name = compileZoneName(qualifyName(rr.Name, origin))
+		if rr.Class == "" {
+			rr.Class = defaultClass
+		}
+		class, ok := classCode(rr.Class)
+		if ok == false {
+			errs = append(errs, &RecordError{Record: rr, Err: fmt.Errorf("unknown class %s: %w", rr.Class, ErrInvalidRdata)})
+			continue
+		}
+		recordType, ok := recordTypeCode(rr.Type)
+		if ok == false {
+			errs = append(errs, &RecordError{Record: rr, Err: fmt.Errorf("unknown type %s: %w", rr.Type, ErrUnsupportedRecord)})
+			continue
+		}
+		rdata, err := compileZoneRdataOf(rr)
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+
+		// BIND keeps the case of the first occurrence of a name.
+		lower := strings.ToLower(rr.Name)
+		if owner, ok := owners[lower]; ok == true {
+			rr.Name = owner
+		}
+		owners[lower] = rr.Name
+
+		// RRSIG records form one set per type that they cover.
+		var covers uint16
+		if recordTypeName(recordType) == "RRSIG" && len(rr.Values) > 0 {
+			covers, _ = recordTypeCode(rr.Values[0])
+		}
+		key := lower + " " + strconv.Itoa(int(class)) + " " + strconv.Itoa(int(recordType)) + " " + strconv.Itoa(int(covers))
+		if seen[key+" "+string(rdata.key)] == true {
+			continue
+		}
+		seen[key+" "+string(rdata.key)] = true
+
+		set, ok := index[key]
+		if ok == false {
+			set = &compileZoneSet{
+				name:       rr.Name,
+				class:      className(class),
+				classCode:  class,
+				recordType: recordTypeName(recordType),
+				typeCode:   recordType,
+				ttl:        rr.TTL,
+				covers:     covers,
+			}
+			index[key] = set
+			sets = append(sets, set)
+		}
+		set.rdata = append(set.rdata, rdata)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	sort.SliceStable(sets, func(i, j int) bool {
+		if order := compareCanonicalNames(sets[i].name, sets[j].name); order != 0 {
+			return order < 0
+		}
+		if sets[i].classCode != sets[j].classCode {
+			return sets[i].classCode < sets[j].classCode
+		}
+		return sets[i].dumpOrder() < sets[j].dumpOrder()
+	})
+
+	buf := bytes.Buffer{}
+	for _, set := range sets {
+		sort.SliceStable(set.rdata, func(i, j int) bool {
+			return bytes.Compare(set.rdata[i].key, set.rdata[j].key) < 0
+		})
+		for _, rdata := range set.rdata {
+			buf.WriteString(set.line(rdata.text) + "\n")
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// dumpOrder ranks record sets at the same name in the order that BIND
+// writes them: SOA, then NS, then by type code, with RRSIG records
+// immediately after the set they cover.
+func (set *compileZoneSet) dumpOrder() int {
+	recordType, sig := int(set.typeCode), 0
+	if set.recordType == "RRSIG" {
+		recordType, sig = int(set.covers), 1
+	}
+	switch recordTypeName(uint16(recordType)) {
+	case "SOA":
+		recordType = 0
+	case "NS":
+		recordType = 1
+	default:
+		recordType += 2
+	}
+	return recordType<<1 + sig
+}
+
+// line renders a record of the set, aligned to BIND's columns.
+func (set *compileZoneSet) line(rdata string) string {
+	str := strings.Builder{}
+	str.WriteString(set.name)
+	column := len(set.name)
+	column = compileZoneIndent(&str, column, compileZoneTtlColumn)
+	ttl := strconv.Itoa(set.ttl)
+	str.WriteString(ttl)
+	column += len(ttl)
+	column = compileZoneIndent(&str, column, compileZoneClassColumn)
+	str.WriteString(set.class)
+	column += len(set.class)
+	column = compileZoneIndent(&str, column, compileZoneTypeColumn)
+	str.WriteString(set.recordType)
+	column += len(set.recordType)
+	compileZoneIndent(&str, column, compileZoneRdataColumn)
+	str.WriteString(rdata)
+	return str.String()
+}
+
+// compileZoneIndent advances from column to the target column the way BIND
+// does: with tabs while a tab stop is within reach, and spaces for the
+// rest. At least one character is always written, so that a field which
+// passes the target column is still separated from the next. It returns the
+// new column.
+func compileZoneIndent(str *strings.Builder, column int, to int) int {
+	if to < column+1 {
+		to = column + 1
+	}
+	tabs := to/compileZoneTabWidth - column/compileZoneTabWidth
+	if tabs > 0 {
+		str.WriteString(strings.Repeat("\t", tabs))
+		column = to / compileZoneTabWidth * compileZoneTabWidth
+	}
+	str.WriteString(strings.Repeat(" ", to-column))
+	return to
+}
+
+// compileZoneName renders an absolute domain name in BIND's presentation
+// format, in which escapes are normalized.
+func compileZoneName(name string) string {
+	wire, err := packName(nil, name)
+	if err != nil {
+		return name
+	}
+	result, _, err := unpackName(wire, 0)
+	if err != nil {
+		return name
+	}
+	return result
+}
+
+// compileZoneRdataOf renders the values of a record in BIND's presentation
+// format, by way of their wire format.
+func compileZoneRdataOf(rr ResourceRecord) (compileZoneRdata, error) {
+	rdata, err := packRdata(rr)
+	if errors.Is(err, ErrUnsupportedRecord) {
+		text := strings.Join(rr.Values, " ")
+		return compileZoneRdata{text: text, key: []byte(strings.ToLower(text))}, nil
+	}
+	if err != nil {
+		return compileZoneRdata{}, err
+	}
+
+	values, err := unpackRdata(rr.Type, rdata)
+	if err != nil {
+		return compileZoneRdata{}, err
+	}
+	schema, ok := rdataSchema(rr.Type)
+	if ok == false || isGenericRdata(values) {
+		// The hexadecimal data of the generic form follows its length.
+		schema = []rdataField{{"generic", rdataToken}, {"length", rdataToken}, {"data", rdataHex}}
+	}
+	for i, v := range values {
+		field, ok := rdataFieldAt(schema, i)
+		if ok == true && (field.kind == rdataBase64 || field.kind == rdataHex) {
+			values[i] = compileZoneWords(v)
+		}
+	}
+
+	// Domain names compare in lower case, per RFC 4034 §6.2.
+	canonical := rr
+	canonical.Values = make([]string, len(values))
+	copy(canonical.Values, values)
+	for i, v := range canonical.Values {
+		if field, ok := rdataFieldAt(schema, i); ok == true && field.kind.isName() {
+			canonical.Values[i] = strings.ToLower(v)
+		}
+	}
+	key, err := packRdata(canonical)
+	if err != nil {
+		key = rdata
+	}
+	return compileZoneRdata{text: strings.Join(values, " "), key: key}, nil
+}
+
+// compileZoneWords breaks base64 or hexadecimal data into space separated
+// words.
+func compileZoneWords(data string) string {
+	words := make([]string, 0, len(data)/compileZoneWordLength+1)
+	for len(data) > compileZoneWordLength {
+		words = append(words, data[:compileZoneWordLength])
+		data = data[compileZoneWordLength:]
+	}
+	return strings.Join(append(words, data), " ")
+}
+
+// compareCanonicalNames compares two absolute domain names in the canonical
+// order of RFC 4034 §6.1: label by label from the right, with each label
+// compared as lower case bytes. Names that cannot be encoded compare as
+// lower case text.
+func compareCanonicalNames(a string, b string) int {
+	labelsA, errA := canonicalLabels(a)
+	labelsB, errB := canonicalLabels(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+	for i, j := len(labelsA)-1, len(labelsB)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if order := bytes.Compare(labelsA[i], labelsB[j]); order != 0 {
+			return order
+		}
+	}
+	return len(labelsA) - len(labelsB)
+}
+
+// canonicalLabels splits an absolute domain name into its lower case
+// labels, in wire format.
+func canonicalLabels(name string) ([][]byte, error) {
+	wire, err := packName(nil, name)
+	if err != nil {
+		return nil, err
+	}
+	result := make([][]byte, 0)
+	for offset := 0; wire[offset] != 0; offset += int(wire[offset]) + 1 {
+		label := make([]byte, wire[offset])
+		for i, b := range wire[offset+1 : offset+1+len(label)] {
+			if b >= 'A' && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+			label[i] = b
+		}
+		result = append(result, label)
+	}
+	return result, nil
+}
@@ -0,0 +1,120 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_CompileZone_Fixtures(t *testing.T) {
+	zp, _ := NewZoneParser()
+	fixtures, err := readFixtures("testdata/compile_zone")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	for name, fix := range fixtures {
+		t.Logf("testing fixture: %s", name)
+		found, err := zp.Parse(fix.input)
+		require.Nil(t, err)
+		buf := bytes.Buffer{}
+		err = WriteCompileZone(&buf, found, "example.com.")
+		assert.Nil(t, err)
+		assert.Equal(t, fix.expected, buf.String())
+	}
+}
+
+// Test_CompileZone_Bind compares the fixtures with the output of BIND, and
+// is skipped when named-compilezone is not installed.
+func Test_CompileZone_Bind(t *testing.T) {
+	compileZone, err := exec.LookPath("named-compilezone")
+	if err != nil {
+		t.Skip("named-compilezone is not installed")
+	}
+	version, err := exec.Command(compileZone, "-v").Output()
+	require.Nil(t, err)
+	t.Logf("named-compilezone version: %s", strings.TrimSpace(string(version)))
+
+	inputs, err := filepath.Glob("testdata/compile_zone/*.txt")
+	require.Nil(t, err)
+	for _, input := range inputs {
+		found, err := exec.Command(compileZone, "-s", "full", "-i", "none", "-o", "-", "example.com", input).Output()
+		require.Nil(t, err, input)
+		expected, err := os.ReadFile(input + ".expected")
+		require.Nil(t, err, input)
+		assert.Equal(t, string(expected), string(found), input)
+	}
+}
+
+func Test_WriteCompileZone(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+www 300 IN RRSIG A 13 3 300 20260101000000 20251201000000 12345 example.com. c2lnbmF0dXJl
+www 300 IN A 192.0.2.1
+www 300 IN RRSIG AAAA 13 3 300 20260101000000 20251201000000 12345 example.com. c2lnbmF0dXJl
+www 300 IN AAAA 2001:db8::1
+www 300 IN A 192.0.2.1
+a-very-long-owner-name-that-passes-the-ttl-column 300 IN A 192.0.2.2
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteCompileZone(&buf, z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "a-very-long-owner-name-that-passes-the-ttl-column.example.com. 300 IN A\t192.0.2.2\n"+
+		"www.example.com.\t\t\t      300 IN A\t\t192.0.2.1\n"+
+		"www.example.com.\t\t\t      300 IN RRSIG\tA 13 3 300 20260101000000 20251201000000 12345 example.com. c2lnbmF0dXJl\n"+
+		"www.example.com.\t\t\t      300 IN AAAA\t2001:db8::1\n"+
+		"www.example.com.\t\t\t      300 IN RRSIG\tAAAA 13 3 300 20260101000000 20251201000000 12345 example.com. c2lnbmF0dXJl\n",
+		buf.String())
+
+	z = mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2
+www 300 IN MX mail
+`)
+	buf.Reset()
+	err = WriteCompileZone(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	assert.Equal(t, 2, strings.Count(err.Error(), ErrInvalidRdata.Error()))
+	assert.Equal(t, "", buf.String())
+}
+
+func Test_compileZoneIndent(t *testing.T) {
+	str := strings.Builder{}
+	assert.Equal(t, 46, compileZoneIndent(&str, 12, 46))
+	assert.Equal(t, "\t\t\t\t      ", str.String())
+
+	str.Reset()
+	assert.Equal(t, 52, compileZoneIndent(&str, 51, 46))
+	assert.Equal(t, " ", str.String())
+
+	str.Reset()
+	assert.Equal(t, 64, compileZoneIndent(&str, 63, 46))
+	assert.Equal(t, "\t", str.String())
+
+	str.Reset()
+	assert.Equal(t, 64, compileZoneIndent(&str, 58, 64))
+	assert.Equal(t, "\t", str.String())
+}
+
+func Test_compareCanonicalNames(t *testing.T) {
+	// The example of RFC 4034 §6.1.
+	names := []string{
+		"example.",
+		"a.example.",
+		"yljkjljk.a.example.",
+		"Z.a.example.",
+		`zABC.a.EXAMPLE.`,
+		"z.example.",
+		`\001.z.example.`,
+		"*.z.example.",
+		`\200.z.example.`,
+	}
+	for i := 0; i < len(names)-1; i++ {
+		assert.Negative(t, compareCanonicalNames(names[i], names[i+1]), names[i])
+		assert.Positive(t, compareCanonicalNames(names[i+1], names[i]), names[i+1])
+	}
+	assert.Zero(t, compareCanonicalNames("WWW.example.com.", "www.EXAMPLE.com."))
+}
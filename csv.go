@@ -0,0 +1,373 @@
+package zone
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSVColumns maps the fields of a record to the columns of a CSV file, by
+// the names in its header row. Columns that are not used are left empty.
+type CSVColumns struct {
+	Name  string
+	TTL   string
+	Class string
+	Type  string
+
+	// Value is the column that holds the values of a record of the type
+	// given in the Type column, in zone file presentation format, e.g.
+	// `10 mail.example.com.` for an MX record. The text of TXT and SPF
+	// records is written without quotes.
+	Value string
+
+	// TypeValues maps record types to columns that only hold values of
+	// that type, e.g. `{"A": "ipv4", "AAAA": "ipv6"}`. A row produces one
+	// record for each of these columns that is not empty.
+	TypeValues map[string]string
+}
+
+// DefaultCSVColumns is the column mapping used unless [WithCSVColumns] is
+// given.
+var DefaultCSVColumns = CSVColumns{
+	Name:  "name",
+	TTL:   "ttl",
+	Class: "class",
+	Type:  "type",
+	Value: "value",
+}
+
+// types lists the record types that have per-type value columns, in a
+// stable order.
+func (columns CSVColumns) types() []string {
+	result := make([]string, 0, len(columns.TypeValues))
+	for recordType := range columns.TypeValues {
+		result = append(result, recordType)
+	}
+	slices.Sort(result)
+	return result
+}
+
+type CSVOption func(format *csvFormat) error
+
+type csvFormat struct {
+	columns CSVColumns
+	comma   rune
+	origin  string
+	ttl     int
+}
+
+// WithCSVColumns sets the column mapping. A name column is required, along
+// with a value column and type column, or at least one per-type value
+// column.
+func WithCSVColumns(columns CSVColumns) CSVOption {
+	return func(format *csvFormat) error {
+		if columns.Name == "" {
+			return errors.New("csv columns require a name column")
+		}
+		if (columns.Value == "" || columns.Type == "") && len(columns.TypeValues) == 0 {
+			return errors.New("csv columns require a type and value column, or per-type value columns")
+		}
+		format.columns = columns
+		return nil
+	}
+}
+
+// WithCSVComma sets the field delimiter, e.g. `;` for files exported by
+// spreadsheets in locales that use a decimal comma. The default is `,`.
+func WithCSVComma(comma rune) CSVOption {
+	return func(format *csvFormat) error {
+		if comma == '"' || comma == '\r' || comma == '\n' {
+			return fmt.Errorf("invalid csv delimiter: %q", comma)
+		}
+		format.comma = comma
+		return nil
+	}
+}
+
+// WithCSVOrigin qualifies relative names, and `@`, with origin. By default,
+// names are read and written as given.
+func WithCSVOrigin(origin string) CSVOption {
+	return func(format *csvFormat) error {
+		format.origin = fqdn(origin)
+		return nil
+	}
+}
+
+// WithCSVTtl sets the TTL of records whose TTL cell is empty, or of every
+// record when there is no TTL column. The default is `86_400`.
+func WithCSVTtl(value int) CSVOption {
+	return func(format *csvFormat) error {
+		if value < 0 {
+			return fmt.Errorf("ttl must not be negative: %d", value)
+		}
+		format.ttl = value
+		return nil
+	}
+}
+
+func newCSVFormat(opts []CSVOption) (*csvFormat, error) {
+	format := &csvFormat{
+		columns: DefaultCSVColumns,
+		comma:   ',',
+		ttl:     defaultTtl,
+	}
+	for _, opt := range opts {
+		err := opt(format)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return format, nil
+}
+
+// ParseCSV reads the records of a CSV file into a [Zone]. The first row is
+// a header that names the columns, which are matched to the mapping set with
+// [WithCSVColumns] case-insensitively. Columns that are not mapped are
+// ignored, and the class defaults to `IN`. The first SOA record becomes the
+// SOA of the zone.
+//
+// Every record is checked with [ResourceRecord.Validate]. Invalid rows are
+// reported as errors that give their row number, counting the header as row
+// 1, and that wrap [ErrInvalidRdata]. All invalid rows are reported, and no
+// zone is returned. The Position of each record holds the lines of the file
+// its row spans.
+func ParseCSV(reader io.Reader, opts ...CSVOption) (*Zone, error) {
+	format, err := newCSVFormat(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(reader)
+	r.Comma = format.comma
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: header: %w", err)
+	}
+	indexes := make(map[string]int)
+	for i, column := range header {
+		indexes[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	index := func(column string) int {
+		if column == "" {
+			return -1
+		}
+		if i, ok := indexes[strings.ToLower(column)]; ok == true {
+			return i
+		}
+		return -1
+	}
+	if index(format.columns.Name) < 0 {
+		return nil, fmt.Errorf("parse csv: missing column %q", format.columns.Name)
+	}
+
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	var errs []error
+	row := 1
+	for {
+		fields, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row += 1
+		if err != nil {
+			return nil, fmt.Errorf("parse csv: row %d: %w", row, err)
+		}
+		startLine, _ := r.FieldPos(0)
+		endLine, _ := r.FieldPos(len(fields) - 1)
+		cell := func(column string) string {
+			i := index(column)
+			if i < 0 || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+
+		records, err := format.records(cell)
+		for _, rr := range records {
+			rr.Position = Position{StartLine: startLine, EndLine: endLine}
+			if err == nil {
+				err = rr.Validate()
+			}
+			if err != nil {
+				break
+			}
+			if strings.EqualFold(rr.Type, "SOA") && result.SOA.IsEmpty() {
+				result.SOA = rr
+				continue
+			}
+			result.Records = append(result.Records, rr)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parse csv: row %d: %w", row, err))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+// records builds the records of a row from its cells.
+func (format *csvFormat) records(cell func(column string) string) ([]ResourceRecord, error) {
+	base := ResourceRecord{
+		Name:   qualifyName(cell(format.columns.Name), format.origin),
+		Class:  cell(format.columns.Class),
+		TTL:    format.ttl,
+		HasTTL: true,
+	}
+	if base.Class == "" {
+		base.Class = defaultClass
+	}
+	if ttl := cell(format.columns.TTL); ttl != "" {
+		if isTtlToken.MatchString(ttl) == false {
+			return nil, fmt.Errorf("invalid ttl %q: %w", ttl, ErrInvalidRdata)
+		}
+		value, err := strconv.Atoi(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ttl %q: %w", ttl, ErrInvalidRdata)
+		}
+		base.TTL = value
+	}
+
+	result := make([]ResourceRecord, 0, 1)
+	recordType := cell(format.columns.Type)
+	if value := cell(format.columns.Value); value != "" {
+		rr := base
+		rr.Type = recordType
+		rr.Values = format.values(recordType, value)
+		result = append(result, rr)
+	}
+	for _, recordType := range format.columns.types() {
+		value := cell(format.columns.TypeValues[recordType])
+		if value == "" {
+			continue
+		}
+		rr := base
+		rr.Type = recordType
+		rr.Values = format.values(recordType, value)
+		result = append(result, rr)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("row has no values: %w", ErrInvalidRdata)
+	}
+	return result, nil
+}
+
+// values converts a value cell into the values of a record. The text of
+// TXT and SPF records is quoted, and split into strings of 255 bytes. Other
+// values are divided into tokens as they are in zone files, and names are
+// qualified with the origin.
+func (format *csvFormat) values(recordType string, value string) []string {
+	if isTextType(recordType) {
+		result := make([]string, 0)
+		for _, chunk := range splitCharacterString(value) {
+			result = append(result, quoteCharacterString(chunk))
+		}
+		return result
+	}
+
+	result := make([]string, 0)
+	for _, token := range tokenizeLine([]byte(value)) {
+		result = append(result, string(token))
+	}
+	rr := qualifyRdata(ResourceRecord{Type: recordType, Values: result}, format.origin)
+	return rr.Values
+}
+
+// WriteCSV writes the records of a zone, starting with its SOA record, as a
+// CSV file with a header row. Fields are quoted as described in RFC 4180
+// when they contain delimiters, quotes, or line breaks.
+//
+// Records whose type has a per-type value column are written to that
+// column. Other records are written to the value column. Names are relative
+// to the origin set with [WithCSVOrigin], with the origin itself written as
+// `@`. Records that have no column to be written to are reported as
+// [RecordError] values, wrapping [ErrUnsupportedRecord], in the returned
+// error, and nothing is written.
+func WriteCSV(w io.Writer, z *Zone, opts ...CSVOption) error {
+	format, err := newCSVFormat(opts)
+	if err != nil {
+		return err
+	}
+
+	columns := make([]string, 0)
+	for _, column := range []string{format.columns.Name, format.columns.TTL, format.columns.Class, format.columns.Type, format.columns.Value} {
+		if column != "" {
+			columns = append(columns, column)
+		}
+	}
+	for _, recordType := range format.columns.types() {
+		columns = append(columns, format.columns.TypeValues[recordType])
+	}
+	positions := make(map[string]int)
+	for i, column := range columns {
+		positions[column] = i
+	}
+
+	records := z.AllRecords()
+
+	var errs []error
+	rows := [][]string{columns}
+	for _, rr := range records {
+		value, err := format.value(rr)
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+		column, recordType := format.columns.Value, strings.ToUpper(rr.Type)
+		for t, typeColumn := range format.columns.TypeValues {
+			if strings.EqualFold(t, rr.Type) {
+				// The column implies the type.
+				column, recordType = typeColumn, ""
+			}
+		}
+		if column == "" {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrUnsupportedRecord})
+			continue
+		}
+
+		fields := make([]string, len(columns))
+		set := func(column string, value string) {
+			if i, ok := positions[column]; ok == true && column != "" {
+				fields[i] = value
+			}
+		}
+		name := rr.Name
+		if format.origin != "" {
+			name, _ = relativeName(qualifyName(name, format.origin), format.origin)
+		}
+		set(format.columns.Name, name)
+		set(format.columns.TTL, strconv.Itoa(rr.TTL))
+		set(format.columns.Class, strings.ToUpper(rr.Class))
+		set(format.columns.Type, recordType)
+		set(column, value)
+		rows = append(rows, fields)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = format.comma
+	return writer.WriteAll(rows)
+}
+
+// value renders the values of a record as a value cell, the reverse of
+// [csvFormat.values].
+func (format *csvFormat) value(rr ResourceRecord) (string, error) {
+	if isTextType(rr.Type) {
+		strs, err := parseCharacterStrings(rr.Values)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(strs, ""), nil
+	}
+	return strings.Join(rr.Values, " "), nil
+}
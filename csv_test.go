@@ -0,0 +1,108 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_ParseCSV(t *testing.T) {
+	input := `Name,TTL,Type,Value,Owner
+@,3600,SOA,ns1 hostmaster 1 7200 3600 1209600 300,ops
+@,,NS,ns1,ops
+@,300,MX,10 mail,ops
+@,300,TXT,"v=spf1 include:_spf.example.net -all, ""quoted""",marketing
+www,300,A,192.0.2.1,
+www,300,AAAA,2001:db8::1,
+mail,,CNAME,www.example.com.,
+`
+	z, err := ParseCSV(strings.NewReader(input), WithCSVOrigin("example.com"), WithCSVTtl(600))
+	require.Nil(t, err)
+	assert.Equal(t, `example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+example.com. 600 IN NS ns1.example.com.
+example.com. 300 IN MX 10 mail.example.com.
+example.com. 300 IN TXT "v=spf1 include:_spf.example.net -all, \"quoted\""
+www.example.com. 300 IN A 192.0.2.1
+www.example.com. 300 IN AAAA 2001:db8::1
+mail.example.com. 600 IN CNAME www.example.com.
+`, z.String())
+	assert.Equal(t, Position{StartLine: 5, EndLine: 5}, z.Records[2].Position)
+
+	z, err = ParseCSV(strings.NewReader("name,value\n\"multi\nline\",1\n"), WithCSVColumns(CSVColumns{Name: "name", TypeValues: map[string]string{"A": "value"}}))
+	assert.ErrorContains(t, err, "row 2:")
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+
+	_, err = ParseCSV(strings.NewReader("host,ttl\nwww,300\n"))
+	assert.ErrorContains(t, err, `missing column "name"`)
+}
+
+func Test_ParseCSV_Errors(t *testing.T) {
+	input := `name,ttl,type,value
+www,300,A,192.0.2.1
+www,5m,A,192.0.2.2
+www,300,A,192.0.2
+www,300,BOGUS,1
+www,300,MX,
+`
+	_, err := ParseCSV(strings.NewReader(input))
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], `parse csv: row 3: invalid ttl "5m"`)
+	assert.Contains(t, lines[1], "parse csv: row 4: address")
+	assert.Contains(t, lines[2], "parse csv: row 5: unknown type BOGUS")
+	assert.Contains(t, lines[3], "parse csv: row 6: row has no values")
+}
+
+func Test_ParseCSV_TypeValues(t *testing.T) {
+	input := "host;v4;v6;notes\nwww;192.0.2.1;2001:db8::1;web\nmail;192.0.2.2;;\n"
+	columns := CSVColumns{
+		Name:       "host",
+		TypeValues: map[string]string{"A": "v4", "AAAA": "v6"},
+	}
+	z, err := ParseCSV(strings.NewReader(input), WithCSVColumns(columns), WithCSVComma(';'), WithCSVOrigin("example.com."), WithCSVTtl(300))
+	require.Nil(t, err)
+	assert.Equal(t, `www.example.com. 300 IN A 192.0.2.1
+www.example.com. 300 IN AAAA 2001:db8::1
+mail.example.com. 300 IN A 192.0.2.2
+`, z.String())
+
+	buf := bytes.Buffer{}
+	err = WriteCSV(&buf, z, WithCSVColumns(columns), WithCSVComma(';'), WithCSVOrigin("example.com."))
+	require.Nil(t, err)
+	assert.Equal(t, "host;v4;v6\nwww;192.0.2.1;\nwww;;2001:db8::1\nmail;192.0.2.2;\n", buf.String())
+
+	z.Records = append(z.Records, ResourceRecord{Name: "mail", Type: "MX", Values: []string{"10", "mail"}})
+	err = WriteCSV(&buf, z, WithCSVColumns(columns))
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+
+	err = WithCSVColumns(CSVColumns{Name: "host"})(&csvFormat{})
+	assert.ErrorContains(t, err, "require a type and value column")
+}
+
+func Test_WriteCSV(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 300 IN TXT "v=spf1 -all, \"quoted\"" "more"
+www 300 IN A 192.0.2.1
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteCSV(&buf, z, WithCSVOrigin("example.com."))
+	require.Nil(t, err)
+	assert.Equal(t, `name,ttl,class,type,value
+@,3600,IN,SOA,ns1 hostmaster 1 7200 3600 1209600 300
+@,300,IN,TXT,"v=spf1 -all, ""quoted""more"
+www,300,IN,A,192.0.2.1
+`, buf.String())
+
+	found, err := ParseCSV(&buf, WithCSVOrigin("example.com."))
+	require.Nil(t, err)
+	assert.Equal(t, `example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+example.com. 300 IN TXT "v=spf1 -all, \"quoted\"more"
+www.example.com. 300 IN A 192.0.2.1
+`, found.String())
+}
@@ -0,0 +1,111 @@
+package zone
+
+import (
+	"slices"
+	"strings"
+)
+
+// ChangeKind identifies how a record set differs between two zones.
+type ChangeKind int
+
+const (
+	// ChangeAdd indicates the record set only exists in the new zone.
+	ChangeAdd ChangeKind = iota
+
+	// ChangeDelete indicates the record set only exists in the old zone.
+	ChangeDelete
+
+	// ChangeUpdate indicates the record set exists in both zones with
+	// differing TTLs or values.
+	ChangeUpdate
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdd:
+		return "add"
+	case ChangeDelete:
+		return "delete"
+	case ChangeUpdate:
+		return "update"
+	}
+	return "unknown"
+}
+
+// RRSetChange describes a record set that differs between two zones.
+type RRSetChange struct {
+	Kind ChangeKind
+
+	// Old is the record set in the old zone. It is empty for [ChangeAdd].
+	Old RRSet
+
+	// New is the record set in the new zone. It is empty for [ChangeDelete].
+	New RRSet
+}
+
+// Diff determines the record set changes that transform the old zone into
+// the new zone. Deletions are listed first, in the order of the old zone,
+// followed by additions and updates in the order of the new zone. This
+// allows the changes to be applied in order without violating CNAME
+// exclusivity.
+//
+// Either zone may be nil, which is equivalent to an empty zone. Thus,
+// `Diff(nil, z)` lists every record set of z as an addition.
+func Diff(old *Zone, new *Zone) []RRSetChange {
+	oldSets := zoneRRSets(old)
+	newSets := zoneRRSets(new)
+
+	oldIndex := make(map[string]RRSet, len(oldSets))
+	for _, set := range oldSets {
+		oldIndex[rrsetKey(set.Records[0])] = set
+	}
+	newIndex := make(map[string]bool, len(newSets))
+	for _, set := range newSets {
+		newIndex[rrsetKey(set.Records[0])] = true
+	}
+
+	result := make([]RRSetChange, 0)
+	for _, set := range oldSets {
+		if newIndex[rrsetKey(set.Records[0])] == false {
+			result = append(result, RRSetChange{Kind: ChangeDelete, Old: set})
+		}
+	}
+	for _, set := range newSets {
+		oldSet, ok := oldIndex[rrsetKey(set.Records[0])]
+		if ok == false {
+			result = append(result, RRSetChange{Kind: ChangeAdd, New: set})
+			continue
+		}
+		if equalRRSets(oldSet, set) == false {
+			result = append(result, RRSetChange{Kind: ChangeUpdate, Old: oldSet, New: set})
+		}
+	}
+	return result
+}
+
+func zoneRRSets(z *Zone) []RRSet {
+	if z == nil {
+		return nil
+	}
+	return z.RRSets()
+}
+
+// equalRRSets determines if two record sets have the same TTL and the same
+// values, irrespective of the order of their records.
+func equalRRSets(a RRSet, b RRSet) bool {
+	if a.TTL != b.TTL || len(a.Records) != len(b.Records) {
+		return false
+	}
+	return slices.Equal(rrsetValues(a), rrsetValues(b))
+}
+
+// rrsetValues renders the values of every record in a set, sorted so that
+// sets may be compared.
+func rrsetValues(set RRSet) []string {
+	result := make([]string, 0, len(set.Records))
+	for _, rr := range set.Records {
+		result = append(result, strings.Join(rr.Values, " "))
+	}
+	slices.Sort(result)
+	return result
+}
@@ -0,0 +1,62 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_Diff(t *testing.T) {
+	old := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.1
+www 300 IN A 192.0.2.2
+ftp 300 IN A 192.0.2.3
+mail 300 IN A 192.0.2.4
+`)
+	new := mustParse(t, `$ORIGIN example.com.
+WWW 300 IN A 192.0.2.2
+www 300 IN A 192.0.2.1
+mail 600 IN A 192.0.2.4
+api 300 IN A 192.0.2.5
+`)
+
+	found := Diff(old, new)
+	require.Len(t, found, 3)
+
+	assert.Equal(t, ChangeDelete, found[0].Kind)
+	assert.Equal(t, "ftp.example.com.", found[0].Old.Name)
+	assert.Empty(t, found[0].New.Records)
+
+	assert.Equal(t, ChangeUpdate, found[1].Kind)
+	assert.Equal(t, 300, found[1].Old.TTL)
+	assert.Equal(t, 600, found[1].New.TTL)
+
+	assert.Equal(t, ChangeAdd, found[2].Kind)
+	assert.Equal(t, "api.example.com.", found[2].New.Name)
+	assert.Equal(t, "add", found[2].Kind.String())
+
+	assert.Empty(t, Diff(new, new))
+
+	found = Diff(nil, new)
+	require.Len(t, found, 3)
+	for _, change := range found {
+		assert.Equal(t, ChangeAdd, change.Kind)
+	}
+}
+
+func Test_RRSets(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+@ 300 IN SOA ns hostmaster 1 2 3 4 5
+www 300 IN A 192.0.2.1
+ftp 300 IN A 192.0.2.3
+www 60 IN A 192.0.2.2
+`)
+
+	found := z.RRSets()
+	require.Len(t, found, 3)
+	assert.Equal(t, "SOA", found[0].Type)
+	assert.Equal(t, "www.example.com.", found[1].Name)
+	assert.Equal(t, 60, found[1].TTL)
+	assert.Len(t, found[1].Records, 2)
+	assert.Equal(t, "ftp.example.com.", found[2].Name)
+}
@@ -0,0 +1,171 @@
+package zone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// WriteDnsmasq writes the records of a zone as [dnsmasq] configuration lines
+// for the zone at apex. Relative names are qualified with apex, and names are
+// written without their trailing dot.
+//
+// A and AAAA records become `host-record` lines, which also provide the PTR
+// records for their addresses. Other PTR records become `ptr-record` lines.
+// CNAME, MX, SRV, and TXT records become `cname`, `mx-host`, `srv-host`,
+// and `txt-record` lines. dnsmasq does not support TTLs on the latter three,
+// so their TTLs are not written.
+//
+// The SOA record, and NS records at apex, are omitted because dnsmasq
+// answers for the names directly. Records that dnsmasq cannot represent,
+// e.g. delegations, wildcards, or CNAME records whose target is not an
+// address record or CNAME record in the zone, are reported as
+// [RecordError] values, wrapping [ErrUnsupportedRecord], in the returned
+// error. All other records are still written.
+//
+// [dnsmasq]: https://thekelleys.org.uk/dnsmasq/docs/dnsmasq-man.html
+func WriteDnsmasq(w io.Writer, z *Zone, apex string) error {
+	apex = fqdn(apex)
+	records := make([]ResourceRecord, 0, len(z.Records))
+	for _, rr := range z.Records {
+		rr = qualifyRdata(rr, apex)
+		rr.Name = qualifyName(rr.Name, apex)
+		records = append(records, rr)
+	}
+
+	// dnsmasq only follows CNAME records to names it knows about, and
+	// host-record lines provide the PTR records for their addresses.
+	known := make(map[string]bool)
+	hostPtrs := make(map[string]bool)
+	for _, rr := range records {
+		switch strings.ToUpper(rr.Type) {
+		case "A", "AAAA":
+			if addr, err := netip.ParseAddr(strings.Join(rr.Values, " ")); err == nil {
+				hostPtrs[strings.ToLower(reverseName(addr.String())+" "+rr.Name)] = true
+			}
+			known[strings.ToLower(rr.Name)] = true
+		case "CNAME":
+			known[strings.ToLower(rr.Name)] = true
+		}
+	}
+
+	var errs []error
+	buf := bytes.Buffer{}
+	for _, rr := range records {
+		recordType := strings.ToUpper(rr.Type)
+		if recordType == "SOA" || (recordType == "NS" && strings.EqualFold(rr.Name, apex)) {
+			continue
+		}
+
+		if recordType == "PTR" && len(rr.Values) == 1 && hostPtrs[strings.ToLower(rr.Name+" "+rr.Values[0])] {
+			continue
+		}
+
+		line, err := dnsmasqLine(rr, known)
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+
+	_, err := buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// dnsmasqLine renders a record as a dnsmasq configuration line.
+func dnsmasqLine(rr ResourceRecord, known map[string]bool) (string, error) {
+	if strings.EqualFold(rr.Class, defaultClass) == false || isWildcardName(rr.Name) {
+		return "", ErrUnsupportedRecord
+	}
+	name := dnsmasqName(rr.Name)
+	ttl := strconv.Itoa(rr.TTL)
+
+	recordType := strings.ToUpper(rr.Type)
+	switch recordType {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(strings.Join(rr.Values, " "))
+		if err != nil || addr.Is4() != (recordType == "A") {
+			return "", fmt.Errorf("invalid address: %w", ErrInvalidRdata)
+		}
+		return "host-record=" + name + "," + addr.String() + "," + ttl, nil
+
+	case "CNAME", "PTR":
+		if len(rr.Values) != 1 {
+			return "", fmt.Errorf("expected 1 value: %w", ErrInvalidRdata)
+		}
+		if recordType == "PTR" {
+			return "ptr-record=" + name + "," + dnsmasqName(rr.Values[0]), nil
+		}
+		if known[strings.ToLower(rr.Values[0])] == false {
+			return "", fmt.Errorf("target %s is not in the zone: %w", rr.Values[0], ErrUnsupportedRecord)
+		}
+		return "cname=" + name + "," + dnsmasqName(rr.Values[0]) + "," + ttl, nil
+
+	case "MX":
+		if len(rr.Values) != 2 {
+			return "", fmt.Errorf("expected 2 values: %w", ErrInvalidRdata)
+		}
+		return "mx-host=" + name + "," + dnsmasqName(rr.Values[1]) + "," + rr.Values[0], nil
+
+	case "SRV":
+		if len(rr.Values) != 4 {
+			return "", fmt.Errorf("expected 4 values: %w", ErrInvalidRdata)
+		}
+		return "srv-host=" + name + "," + dnsmasqName(rr.Values[3]) + "," + rr.Values[2] + "," + rr.Values[0] + "," + rr.Values[1], nil
+
+	case "TXT":
+		strs, err := parseCharacterStrings(rr.Values)
+		if err != nil {
+			return "", err
+		}
+		fields := []string{"txt-record=" + name}
+		for _, str := range strs {
+			field, err := dnsmasqString(str)
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, field)
+		}
+		return strings.Join(fields, ","), nil
+	}
+
+	return "", ErrUnsupportedRecord
+}
+
+// dnsmasqName renders an absolute domain name without its trailing dot.
+func dnsmasqName(name string) string {
+	if name == "." {
+		return name
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// dnsmasqString quotes a string for a `txt-record` line. Quotes and
+// backslashes are escaped. dnsmasq has no escape for arbitrary bytes, so
+// strings with bytes outside of printable ASCII are rejected.
+func dnsmasqString(value string) (string, error) {
+	str := strings.Builder{}
+	str.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case b == quoteByte || b == escapeByte:
+			str.WriteByte(escapeByte)
+			str.WriteByte(b)
+		case b < 0x20 || b > 0x7e:
+			return "", fmt.Errorf("text includes byte %d: %w", b, ErrUnsupportedRecord)
+		default:
+			str.WriteByte(b)
+		}
+	}
+	str.WriteByte('"')
+	return str.String(), nil
+}
@@ -0,0 +1,43 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_WriteDnsmasq(t *testing.T) {
+	z := mustParse(t, localResolverSample)
+
+	buf := bytes.Buffer{}
+	err := WriteDnsmasq(&buf, z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, `mx-host=example.com,mail.example.com,10
+txt-record=example.com,"v=spf1 -all","it's"
+host-record=www.example.com,192.0.2.1,300
+host-record=www.example.com,2001:db8::1,300
+cname=mail.example.com,www.example.com,300
+srv-host=_sip._tcp.example.com,sip.example.com,5060,10,5
+ptr-record=2.2.0.192.in-addr.arpa,host.example.net
+`, buf.String())
+}
+
+func Test_WriteDnsmasq_Unsupported(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+sub 300 IN NS ns1.example.net.
+* 300 IN A 192.0.2.1
+ext 300 IN CNAME www.example.net.
+@ 300 IN TXT "caf\233"
+@ 300 IN CAA 0 issue "ca.example"
+www 300 IN A 192.0.2.2
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteDnsmasq(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.Equal(t, 5, strings.Count(err.Error(), ErrUnsupportedRecord.Error()))
+	assert.ErrorContains(t, err, "target www.example.net. is not in the zone")
+	assert.Equal(t, "host-record=www.example.com,192.0.2.2,300\n", buf.String())
+}
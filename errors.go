@@ -1,5 +1,56 @@
 package zone
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var ErrNotImplemented = errors.New("feature is not implemented")
+
+// ErrOutOfZone indicates a record's owner name is not at or below the apex
+// of the zone it is being exported from.
+var ErrOutOfZone = errors.New("record is outside of the zone apex")
+
+// ErrUnsupportedRecord indicates a record cannot be represented by the
+// format it is being exported to.
+var ErrUnsupportedRecord = errors.New("record is not supported by the format")
+
+// ErrInvalidRdata indicates a record's values cannot be interpreted.
+var ErrInvalidRdata = errors.New("invalid record data")
+
+// ErrUnknownFormat indicates that no encoder or decoder is registered for a
+// format name.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ErrNoOrigin indicates that a format needs the origin of a zone, e.g. to
+// qualify relative names, and none was given.
+var ErrNoOrigin = errors.New("format requires an origin")
+
+// ErrZoneInvariant indicates that a change to a zone would break one of the
+// invariants that [Zone.Add] describes.
+var ErrZoneInvariant = errors.New("zone invariant violated")
+
+// ErrUnsafeRewrite indicates that a value of a record mentions a name that
+// is being rewritten, but cannot be rewritten safely, e.g. by
+// [Zone.Reorigin].
+var ErrUnsafeRewrite = errors.New("value cannot be rewritten safely")
+
+// RecordError reports a problem with a specific record. Exporters that
+// reject several records return the individual errors joined with
+// [errors.Join].
+type RecordError struct {
+	Record ResourceRecord
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	description := fmt.Sprintf("%s %s: %s", e.Record.Name, e.Record.Type, e.Err)
+	if e.Record.Position.IsValid() {
+		return e.Record.Position.String() + ": " + description
+	}
+	return description
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
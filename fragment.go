@@ -0,0 +1,99 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Fragment is a set of records whose relative names have not been bound to
+// an origin yet, e.g. a file of records without `$ORIGIN` or SOA, as gdns
+// reads them. The same fragment can be bound to many domains, and moved
+// from one to another.
+//
+// The records are kept as written, so that a name that was relative is
+// always qualified with the origin the fragment is bound to, while absolute
+// names are kept, and must lie within that origin.
+type Fragment struct {
+	zone   *Zone
+	origin string
+}
+
+// NewFragment creates a fragment of the records of a zone that was parsed
+// without an origin. The zone is copied.
+func NewFragment(z *Zone) *Fragment {
+	return &Fragment{zone: z.Clone()}
+}
+
+// ParseFragment reads a fragment with a parser created with the given
+// options, which must not include [WithOrigin].
+func ParseFragment(reader io.Reader, opts ...Option) (*Fragment, error) {
+	zp, err := NewZoneParser(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if zp.origin != "" {
+		return nil, errors.New("a fragment cannot be parsed with an origin")
+	}
+	z, err := zp.Parse(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Fragment{zone: z}, nil
+}
+
+// Origin returns the origin the fragment is bound to, or an empty string
+// when it has not been bound.
+func (f *Fragment) Origin() string {
+	return f.origin
+}
+
+// Bind qualifies the relative names of the fragment, including those in
+// values, with origin, and returns the records as a zone. The fragment must
+// not be bound already, see [Fragment.Rebind].
+//
+// Owner names that are absolute, but do not lie within origin, are reported
+// as [RecordError] values wrapping [ErrOutOfZone], and the fragment is not
+// bound.
+func (f *Fragment) Bind(origin string) (*Zone, error) {
+	if f.origin != "" {
+		return nil, fmt.Errorf("fragment is already bound to %s", f.origin)
+	}
+	return f.bind(origin)
+}
+
+// Rebind moves a bound fragment to another origin, as [Fragment.Bind]
+// does. The names that were relative are qualified with the new origin. When
+// an error is returned, the fragment stays bound to its current origin.
+func (f *Fragment) Rebind(origin string) (*Zone, error) {
+	if f.origin == "" {
+		return nil, errors.New("fragment is not bound")
+	}
+	return f.bind(origin)
+}
+
+func (f *Fragment) bind(origin string) (*Zone, error) {
+	if origin == "" {
+		return nil, errors.New("origin must not be empty")
+	}
+	origin = fqdn(origin)
+	result := qualifyZone(f.zone.Clone(), origin)
+
+	var errs []error
+	check := func(rr ResourceRecord) {
+		if isInZone(rr.Name, origin) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrOutOfZone})
+		}
+	}
+	if result.SOA.IsEmpty() == false {
+		check(result.SOA)
+	}
+	for _, rr := range result.Records {
+		check(rr)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	f.origin = origin
+	return result, nil
+}
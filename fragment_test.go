@@ -0,0 +1,70 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const sampleFragment = `@ 300 IN MX 10 mail
+@ 300 IN TXT "v=spf1 include:_spf.example.org. -all"
+www 300 IN CNAME @
+mail 300 IN A 192.0.2.1
+_dmarc 300 IN TXT "v=DMARC1; p=reject"
+`
+
+func Test_Fragment(t *testing.T) {
+	fragment, err := ParseFragment(strings.NewReader(sampleFragment))
+	require.Nil(t, err)
+	assert.Equal(t, "", fragment.Origin())
+
+	_, err = fragment.Rebind("example.net.")
+	assert.ErrorContains(t, err, "fragment is not bound")
+
+	z, err := fragment.Bind("example.com")
+	require.Nil(t, err)
+	assert.Equal(t, "example.com.", fragment.Origin())
+	assert.Equal(t, `example.com. 300 IN MX 10 mail.example.com.
+example.com. 300 IN TXT "v=spf1 include:_spf.example.org. -all"
+www.example.com. 300 IN CNAME example.com.
+mail.example.com. 300 IN A 192.0.2.1
+_dmarc.example.com. 300 IN TXT "v=DMARC1; p=reject"
+`, z.String())
+
+	_, err = fragment.Bind("example.net.")
+	assert.ErrorContains(t, err, "fragment is already bound to example.com.")
+
+	z, err = fragment.Rebind("example.net.")
+	require.Nil(t, err)
+	assert.Equal(t, "example.net.", fragment.Origin())
+	assert.Equal(t, "www.example.net. 300 IN CNAME example.net.\n", z.Records[2].String())
+	assert.Equal(t, "mail.example.net.", z.Records[0].Values[1])
+}
+
+func Test_Fragment_OutOfZone(t *testing.T) {
+	fragment, err := ParseFragment(strings.NewReader("www 300 IN A 192.0.2.1\n" +
+		"www.example.com. 300 IN A 192.0.2.2\n" +
+		"mail 300 IN MX 10 mx.example.org.\n"))
+	require.Nil(t, err)
+
+	z, err := fragment.Bind("example.com.")
+	require.Nil(t, err)
+	assert.Len(t, z.Records, 3)
+
+	_, err = fragment.Rebind("example.net.")
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.ErrorContains(t, err, "2: www.example.com. A: ")
+	assert.Equal(t, "example.com.", fragment.Origin())
+
+	_, err = ParseFragment(strings.NewReader(""), WithOrigin("example.com."))
+	assert.ErrorContains(t, err, "cannot be parsed with an origin")
+
+	// The zone given to a fragment is copied.
+	source := mustParse(t, "www 300 IN A 192.0.2.1\n")
+	fragment = NewFragment(source)
+	source.Records[0].Name = "ftp"
+	z, err = fragment.Bind("example.com.")
+	require.Nil(t, err)
+	assert.Equal(t, "www.example.com.", z.Records[0].Name)
+}
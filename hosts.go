@@ -0,0 +1,218 @@
+package zone
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+type HostsOption func(parser *hostsParser) error
+
+type hostsParser struct {
+	cnameAliases bool
+	origin       string
+	ttl          int
+}
+
+// WithHostsCNAMEAliases will read the aliases of a host as CNAME records
+// that target the host's canonical name when value is `true`. By default,
+// every alias receives its own address records.
+func WithHostsCNAMEAliases(value bool) HostsOption {
+	return func(parser *hostsParser) error {
+		parser.cnameAliases = value
+		return nil
+	}
+}
+
+// WithHostsOrigin qualifies names that do not contain a dot, e.g.
+// `localhost`, with origin. By default, such names are kept as relative
+// names.
+func WithHostsOrigin(origin string) HostsOption {
+	return func(parser *hostsParser) error {
+		parser.origin = fqdn(origin)
+		return nil
+	}
+}
+
+// WithHostsTtl sets the TTL of every record. The default is `86_400`.
+func WithHostsTtl(value int) HostsOption {
+	return func(parser *hostsParser) error {
+		if value < 0 {
+			return fmt.Errorf("ttl must not be negative: %d", value)
+		}
+		parser.ttl = value
+		return nil
+	}
+}
+
+// ParseHosts reads a [hosts(5)] file into a [Zone] of A and AAAA records.
+// Each line lists an address, the canonical name of the host, and its
+// aliases. Comments start with `#`.
+//
+// Names that contain a dot are taken to be fully qualified. Repeated
+// records, such as an alias listed for the same address twice, are only
+// included once.
+//
+// [hosts(5)]: https://man7.org/linux/man-pages/man5/hosts.5.html
+func ParseHosts(reader io.Reader, opts ...HostsOption) (*Zone, error) {
+	parser := &hostsParser{
+		ttl: defaultTtl,
+	}
+	for _, opt := range opts {
+		err := opt(parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	seen := make(map[string]bool)
+	cnames := make(map[string]ResourceRecord)
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 {
+			return nil, fmt.Errorf("parse hosts: line %d: %s has no host name", lineNumber, fields[0])
+		}
+
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil || addr.Zone() != "" {
+			return nil, fmt.Errorf("parse hosts: line %d: invalid address %s", lineNumber, fields[0])
+		}
+		addr = addr.Unmap()
+		recordType := "A"
+		if addr.Is6() {
+			recordType = "AAAA"
+		}
+
+		canonical := parser.name(fields[1])
+		for i, field := range fields[1:] {
+			rr := ResourceRecord{
+				Name:     parser.name(field),
+				Class:    defaultClass,
+				Type:     recordType,
+				TTL:      parser.ttl,
+				HasTTL:   true,
+				Values:   []string{addr.String()},
+				Position: Position{StartLine: lineNumber, EndLine: lineNumber},
+			}
+			if i > 0 && strings.EqualFold(rr.Name, canonical) {
+				continue
+			}
+			if i > 0 && parser.cnameAliases == true {
+				rr.Type, rr.Values = "CNAME", []string{canonical}
+			}
+
+			key := strings.ToLower(rr.Name + " " + rr.Type + " " + rr.Values[0])
+			if seen[key] == true {
+				continue
+			}
+			seen[key] = true
+			if rr.Type == "CNAME" {
+				cnames[strings.ToLower(rr.Name)] = rr
+			}
+			result.Records = append(result.Records, rr)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("parse hosts: %w", err)
+	}
+
+	// A CNAME record must be the only record of its name.
+	for _, rr := range result.Records {
+		cname, ok := cnames[strings.ToLower(rr.Name)]
+		if ok == true && (rr.Type != "CNAME" || rr.Position != cname.Position) {
+			return nil, fmt.Errorf("parse hosts: line %d: alias %s conflicts with line %d: %w", cname.Position.StartLine, cname.Name, rr.Position.StartLine, ErrInvalidRdata)
+		}
+	}
+	return result, nil
+}
+
+// name converts a host name into a domain name.
+func (parser *hostsParser) name(host string) string {
+	if strings.Contains(host, ".") {
+		return fqdn(host)
+	}
+	return qualifyName(host, parser.origin)
+}
+
+// WriteHosts writes the A and AAAA records of a zone as a [hosts(5)] file.
+// Relative names are qualified with origin, and names are written without
+// their trailing dot.
+//
+// Each address is written on a single line. The first name with the address
+// becomes the canonical name of the host, and further names, along with the
+// names of CNAME records that target the canonical name, become its aliases.
+// All other records are ignored.
+//
+// [hosts(5)]: https://man7.org/linux/man-pages/man5/hosts.5.html
+func WriteHosts(w io.Writer, z *Zone, origin string) error {
+	if origin != "" {
+		origin = fqdn(origin)
+	}
+	host := func(name string) string {
+		name = qualifyName(name, origin)
+		if name == "." {
+			return name
+		}
+		return strings.TrimSuffix(name, ".")
+	}
+
+	addresses := make([]string, 0)
+	names := make(map[string][]string)
+	aliases := make(map[string][]string)
+	for _, rr := range z.Records {
+		recordType := strings.ToUpper(rr.Type)
+		if recordType == "CNAME" && len(rr.Values) == 1 {
+			target := strings.ToLower(host(qualifyRdata(rr, origin).Values[0]))
+			aliases[target] = append(aliases[target], host(rr.Name))
+			continue
+		}
+		if recordType != "A" && recordType != "AAAA" {
+			continue
+		}
+
+		addr, err := netip.ParseAddr(strings.Join(rr.Values, " "))
+		if err != nil || addr.Is4() != (recordType == "A") {
+			return &RecordError{Record: rr, Err: fmt.Errorf("invalid address: %w", ErrInvalidRdata)}
+		}
+		address := addr.String()
+		if _, ok := names[address]; ok == false {
+			addresses = append(addresses, address)
+		}
+		names[address] = appendHost(names[address], host(rr.Name))
+	}
+
+	buf := bytes.Buffer{}
+	for _, address := range addresses {
+		hosts := names[address]
+		for _, alias := range aliases[strings.ToLower(hosts[0])] {
+			hosts = appendHost(hosts, alias)
+		}
+		buf.WriteString(address + "\t" + strings.Join(hosts, " ") + "\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// appendHost adds a host name to a list of names, unless the list already
+// includes it.
+func appendHost(hosts []string, host string) []string {
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return hosts
+		}
+	}
+	return append(hosts, host)
+}
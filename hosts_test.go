@@ -0,0 +1,116 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const hostsSample = `# lab inventory
+127.0.0.1	localhost
+::1		localhost ip6-localhost ip6-loopback
+192.0.2.10	build.lab.example.com build   # the build server
+192.0.2.11	db1 db
+192.0.2.11	db1 db
+
+2001:db8::10	build.lab.example.com
+`
+
+func Test_ParseHosts(t *testing.T) {
+	z, err := ParseHosts(strings.NewReader(hostsSample))
+	require.Nil(t, err)
+
+	found := make([]string, 0, len(z.Records))
+	for _, rr := range z.Records {
+		found = append(found, rr.String())
+	}
+	assert.Equal(t, []string{
+		"localhost 86400 IN A 127.0.0.1\n",
+		"localhost 86400 IN AAAA ::1\n",
+		"ip6-localhost 86400 IN AAAA ::1\n",
+		"ip6-loopback 86400 IN AAAA ::1\n",
+		"build.lab.example.com. 86400 IN A 192.0.2.10\n",
+		"build 86400 IN A 192.0.2.10\n",
+		"db1 86400 IN A 192.0.2.11\n",
+		"db 86400 IN A 192.0.2.11\n",
+		"build.lab.example.com. 86400 IN AAAA 2001:db8::10\n",
+	}, found)
+	assert.Equal(t, Position{StartLine: 4, EndLine: 4}, z.Records[4].Position)
+}
+
+func Test_ParseHosts_Options(t *testing.T) {
+	z, err := ParseHosts(
+		strings.NewReader(hostsSample),
+		WithHostsCNAMEAliases(true),
+		WithHostsOrigin("lab.example.com"),
+		WithHostsTtl(300),
+	)
+	require.Nil(t, err)
+
+	found := make([]string, 0, len(z.Records))
+	for _, rr := range z.Records {
+		found = append(found, rr.String())
+	}
+	assert.Equal(t, []string{
+		"localhost.lab.example.com. 300 IN A 127.0.0.1\n",
+		"localhost.lab.example.com. 300 IN AAAA ::1\n",
+		"ip6-localhost.lab.example.com. 300 IN CNAME localhost.lab.example.com.\n",
+		"ip6-loopback.lab.example.com. 300 IN CNAME localhost.lab.example.com.\n",
+		"build.lab.example.com. 300 IN A 192.0.2.10\n",
+		"db1.lab.example.com. 300 IN A 192.0.2.11\n",
+		"db.lab.example.com. 300 IN CNAME db1.lab.example.com.\n",
+		"build.lab.example.com. 300 IN AAAA 2001:db8::10\n",
+	}, found)
+
+	_, err = ParseHosts(strings.NewReader("192.0.2.1 a b\n192.0.2.2 b\n"), WithHostsCNAMEAliases(true))
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	assert.ErrorContains(t, err, "line 1: alias b conflicts with line 2")
+
+	_, err = ParseHosts(strings.NewReader("192.0.2.1 a\n192.0.2 b\n"))
+	assert.ErrorContains(t, err, "line 2: invalid address 192.0.2")
+
+	_, err = ParseHosts(strings.NewReader("192.0.2.1\n"))
+	assert.ErrorContains(t, err, "line 1: 192.0.2.1 has no host name")
+
+	_, err = ParseHosts(strings.NewReader(""), WithHostsTtl(-1))
+	assert.Error(t, err)
+}
+
+func Test_WriteHosts(t *testing.T) {
+	z := mustParse(t, `$ORIGIN lab.example.com.
+@ 300 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+build 300 IN A 192.0.2.10
+ci 300 IN CNAME build
+build 300 IN AAAA 2001:db8::10
+builder 300 IN A 192.0.2.10
+db.example.net. 300 IN A 192.0.2.11
+@ 300 IN MX 10 mail
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteHosts(&buf, z, "lab.example.com.")
+	require.Nil(t, err)
+	assert.Equal(t, `192.0.2.10	build.lab.example.com builder.lab.example.com ci.lab.example.com
+2001:db8::10	build.lab.example.com ci.lab.example.com
+192.0.2.11	db.example.net
+`, buf.String())
+
+	z = mustParse(t, "www.example.com. 300 IN A 2001:db8::1\n")
+	err = WriteHosts(&buf, z, "")
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+}
+
+func Test_Hosts_RoundTrip(t *testing.T) {
+	z, err := ParseHosts(strings.NewReader(hostsSample), WithHostsOrigin("lab.example.com."))
+	require.Nil(t, err)
+
+	buf := bytes.Buffer{}
+	err = WriteHosts(&buf, z, "")
+	require.Nil(t, err)
+	found, err := ParseHosts(&buf, WithHostsOrigin("lab.example.com."))
+	require.Nil(t, err)
+
+	assert.Empty(t, Diff(z, found))
+}
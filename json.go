@@ -0,0 +1,258 @@
+package zone
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// rdataMemberPrefix prefixes the RFC 8427 members that carry RDATA in
+// presentation format, e.g. `rdataMX`.
+const rdataMemberPrefix = "rdata"
+
+// rdataHexMember is the RFC 8427 member that carries RDATA as hexadecimal.
+const rdataHexMember = "RDATAHEX"
+
+// genericRdataToken introduces RDATA in the RFC 3597 generic form, i.e.
+// `\# <length> <hex>`.
+const genericRdataToken = `\#`
+
+// extensionMember is the member that carries what RFC 8427 cannot express,
+// e.g. the positions and comments the parser records. RFC 8427 allows
+// members it does not define, which readers are to ignore.
+const extensionMember = "x-gozone"
+
+// zoneJSON is the RFC 8427 shape of a [Zone]. The records of a zone are
+// carried the same way as the answer section of a zone transfer.
+type zoneJSON struct {
+	AnswerRRs []ResourceRecord `json:"answerRRs"`
+	Extension *zoneExtension   `json:"x-gozone,omitempty"`
+}
+
+type zoneExtension struct {
+	Comments []string `json:"comments"`
+}
+
+// recordExtension holds the fields of a [ResourceRecord] that have no RFC
+// 8427 member.
+type recordExtension struct {
+	Position   *Position         `json:"position,omitempty"`
+	Provenance *Provenance       `json:"provenance,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Comments   []string          `json:"comments,omitempty"`
+}
+
+// MarshalJSON renders the zone as an RFC 8427 object whose `answerRRs`
+// member lists the SOA record, if present, followed by all other records.
+// The comments of the zone are given by the `x-gozone` member.
+func (z Zone) MarshalJSON() ([]byte, error) {
+	obj := zoneJSON{AnswerRRs: z.AllRecords()}
+	if z.Comments != nil {
+		obj.Extension = &zoneExtension{Comments: z.Comments}
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON reads a zone from the shape produced by [Zone.MarshalJSON].
+// If the first record is a SOA record it becomes the zone's SOA.
+func (z *Zone) UnmarshalJSON(data []byte) error {
+	var obj zoneJSON
+	err := json.Unmarshal(data, &obj)
+	if err != nil {
+		return err
+	}
+
+	result := Zone{
+		Records: make([]ResourceRecord, 0, len(obj.AnswerRRs)),
+	}
+	if obj.Extension != nil {
+		result.Comments = obj.Extension.Comments
+	}
+	for i, rr := range obj.AnswerRRs {
+		if i == 0 && strings.EqualFold(rr.Type, "SOA") {
+			result.SOA = rr
+			continue
+		}
+		result.Records = append(result.Records, rr)
+	}
+	*z = result
+	return nil
+}
+
+// MarshalJSON renders the record as an RFC 8427 resource record object, e.g.:
+//
+//	{"CLASS":1,"CLASSname":"IN","NAME":"example.com.","TTL":300,"TYPE":15,"TYPEname":"MX","rdataMX":"10 mail.example.com."}
+//
+// The RDATA is given in presentation format by an `rdata<TYPE>` member.
+// Values in the RFC 3597 generic form (`\# <length> <hex>`) are given by the
+// `RDATAHEX` member instead. Fields that are absent from the record, such as
+// an omitted class, are omitted from the object.
+//
+// The Position, Provenance, Metadata, and Comments of the record, which RFC
+// 8427 has no members for, are given by an `x-gozone` object, so that
+// reading the object again yields an identical record. The member is
+// omitted when the record has none of them.
+func (rr ResourceRecord) MarshalJSON() ([]byte, error) {
+	if rr.Type == "" {
+		return nil, fmt.Errorf("marshal record %s: record has no type", rr.Position)
+	}
+
+	obj := map[string]any{
+		"TYPEname": rr.Type,
+	}
+	if code, ok := recordTypeCode(rr.Type); ok == true {
+		obj["TYPE"] = code
+	}
+	if rr.Name != "" {
+		obj["NAME"] = rr.Name
+	}
+	if rr.Class != "" {
+		obj["CLASSname"] = rr.Class
+		if code, ok := classCode(rr.Class); ok == true {
+			obj["CLASS"] = code
+		}
+	}
+	if rr.HasTTL == true || rr.TTL > 0 {
+		obj["TTL"] = rr.TTL
+	}
+
+	if isGenericRdata(rr.Values) {
+		obj[rdataHexMember] = strings.Join(rr.Values[2:], "")
+	} else if len(rr.Values) > 0 {
+		obj[rdataMemberPrefix+strings.ToUpper(rr.Type)] = strings.Join(rr.Values, " ")
+	}
+
+	extension := recordExtension{
+		Provenance: rr.Provenance,
+		Metadata:   rr.Metadata,
+		Comments:   rr.Comments,
+	}
+	if rr.Position != (Position{}) {
+		extension.Position = &rr.Position
+	}
+	if extension.Position != nil || extension.Provenance != nil || rr.Metadata != nil || rr.Comments != nil {
+		obj[extensionMember] = extension
+	}
+
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON reads an RFC 8427 resource record object. The mnemonic
+// members, `TYPEname` and `CLASSname`, are preferred over the numeric
+// members. `RDATAHEX` is converted to the RFC 3597 generic form. The
+// `x-gozone` member, if present, restores the fields that RFC 8427 cannot
+// express.
+func (rr *ResourceRecord) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	err := json.Unmarshal(data, &members)
+	if err != nil {
+		return err
+	}
+
+	member := func(name string, target any) (bool, error) {
+		raw, ok := members[name]
+		if ok == false {
+			return false, nil
+		}
+		err := json.Unmarshal(raw, target)
+		if err != nil {
+			return false, fmt.Errorf("unmarshal %s: %w", name, err)
+		}
+		return true, nil
+	}
+
+	result := ResourceRecord{}
+	if _, err = member("NAME", &result.Name); err != nil {
+		return err
+	}
+
+	found, err := member("TYPEname", &result.Type)
+	if err != nil {
+		return err
+	}
+	if found == false {
+		var code uint16
+		if found, err = member("TYPE", &code); err != nil {
+			return err
+		} else if found == true {
+			result.Type = recordTypeName(code)
+		}
+	}
+
+	found, err = member("CLASSname", &result.Class)
+	if err != nil {
+		return err
+	}
+	if found == false {
+		var code uint16
+		if found, err = member("CLASS", &code); err != nil {
+			return err
+		} else if found == true {
+			result.Class = className(code)
+		}
+	}
+
+	if result.HasTTL, err = member("TTL", &result.TTL); err != nil {
+		return err
+	}
+
+	var rdataHex string
+	if found, err = member(rdataHexMember, &rdataHex); err != nil {
+		return err
+	} else if found == true {
+		rdata, err := hex.DecodeString(rdataHex)
+		if err != nil {
+			return fmt.Errorf("unmarshal %s: %w", rdataHexMember, err)
+		}
+		result.Values = []string{genericRdataToken, strconv.Itoa(len(rdata))}
+		if len(rdata) > 0 {
+			result.Values = append(result.Values, rdataHex)
+		}
+	} else if name, ok := rdataMemberName(members, result.Type); ok == true {
+		var rdata string
+		if _, err = member(name, &rdata); err != nil {
+			return err
+		}
+		for _, token := range tokenizeLine([]byte(rdata)) {
+			result.Values = append(result.Values, string(token))
+		}
+	}
+
+	var extension recordExtension
+	if found, err = member(extensionMember, &extension); err != nil {
+		return err
+	} else if found == true {
+		if extension.Position != nil {
+			result.Position = *extension.Position
+		}
+		result.Provenance = extension.Provenance
+		result.Metadata = extension.Metadata
+		result.Comments = extension.Comments
+	}
+
+	*rr = result
+	return nil
+}
+
+// isGenericRdata determines if a set of record values is in the RFC 3597
+// generic form, i.e. `\# <length> <hex>`.
+func isGenericRdata(values []string) bool {
+	return len(values) >= 2 && values[0] == genericRdataToken
+}
+
+// rdataMemberName finds the member that carries the presentation format
+// RDATA. The member matching the record type is preferred.
+func rdataMemberName(members map[string]json.RawMessage, recordType string) (string, bool) {
+	name := rdataMemberPrefix + strings.ToUpper(recordType)
+	if _, ok := members[name]; ok == true {
+		return name, true
+	}
+	for name = range members {
+		if strings.HasPrefix(name, rdataMemberPrefix) {
+			return name, true
+		}
+	}
+	return "", false
+}
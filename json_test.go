@@ -0,0 +1,115 @@
+package zone
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_ResourceRecord_MarshalJSON(t *testing.T) {
+	rr := ResourceRecord{
+		Name:   "example.com.",
+		Class:  "IN",
+		Type:   "MX",
+		TTL:    300,
+		Values: []string{"10", "mail.example.com."},
+	}
+	found, err := json.Marshal(rr)
+	require.Nil(t, err)
+	assert.JSONEq(
+		t,
+		`{"NAME":"example.com.","TYPE":15,"TYPEname":"MX","CLASS":1,"CLASSname":"IN","TTL":300,"rdataMX":"10 mail.example.com."}`,
+		string(found),
+	)
+
+	// Absent fields are omitted, an explicit TTL of 0 is not.
+	rr = ResourceRecord{
+		Type:   "TYPE65534",
+		HasTTL: true,
+		Values: []string{`\#`, "4", "0A000001"},
+	}
+	found, err = json.Marshal(rr)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"TYPE":65534,"TYPEname":"TYPE65534","TTL":0,"RDATAHEX":"0A000001"}`, string(found))
+
+	// The fields RFC 8427 cannot express are kept in an extension member.
+	rr = ResourceRecord{
+		Name:     "example.com.",
+		Type:     "A",
+		Values:   []string{"192.0.2.1"},
+		Position: Position{File: "db.example", StartLine: 3, EndLine: 3},
+		Metadata: map[string]string{MetadataTinyDNSLocation: "ex"},
+		Comments: []string{" web server"},
+	}
+	found, err = json.Marshal(rr)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"NAME":"example.com.","TYPE":1,"TYPEname":"A","rdataA":"192.0.2.1","x-gozone":{
+		"position":{"File":"db.example","StartLine":3,"EndLine":3},
+		"metadata":{"`+MetadataTinyDNSLocation+`":"ex"},
+		"comments":[" web server"]
+	}}`, string(found))
+	var decoded ResourceRecord
+	require.Nil(t, json.Unmarshal(found, &decoded))
+	assert.Equal(t, rr, decoded)
+
+	_, err = json.Marshal(ResourceRecord{Name: "foo"})
+	assert.ErrorContains(t, err, "record has no type")
+}
+
+func Test_ResourceRecord_UnmarshalJSON(t *testing.T) {
+	var found ResourceRecord
+	err := json.Unmarshal(
+		[]byte(`{"NAME":"example.com.","TYPE":16,"CLASS":1,"rdataTXT":"\"v=spf1 -all\" \"foo\""}`),
+		&found,
+	)
+	require.Nil(t, err)
+	assert.Equal(t, ResourceRecord{
+		Name:   "example.com.",
+		Class:  "IN",
+		Type:   "TXT",
+		Values: []string{`"v=spf1 -all"`, `"foo"`},
+	}, found)
+
+	err = json.Unmarshal([]byte(`{"NAME":"a.","TYPE":1,"TTL":0,"RDATAHEX":"0a000001"}`), &found)
+	require.Nil(t, err)
+	assert.Equal(t, ResourceRecord{
+		Name:   "a.",
+		Type:   "A",
+		HasTTL: true,
+		Values: []string{`\#`, "4", "0a000001"},
+	}, found)
+
+	err = json.Unmarshal([]byte(`{"TYPE":1,"RDATAHEX":"zz"}`), &found)
+	assert.ErrorContains(t, err, "unmarshal RDATAHEX")
+
+	err = json.Unmarshal([]byte(`{"TTL":"300"}`), &found)
+	assert.ErrorContains(t, err, "unmarshal TTL")
+}
+
+func Test_Zone_JSON_RoundTrip(t *testing.T) {
+	zp, _ := NewZoneParser(WithFileName("fixture.txt"), WithProvenance(true), WithComments(true))
+	for _, dir := range []string{"testdata", "testdata/bind9", "testdata/prefer_soa_min_ttl", "testdata/microsoft"} {
+		fixtures, err := readFixtures(dir)
+		require.Nil(t, err)
+
+		for name, fix := range fixtures {
+			t.Logf("testing fixture: %s/%s", dir, name)
+			parser := zp
+			if dir == "testdata/microsoft" {
+				parser, _ = NewZoneParser(WithFileName("fixture.txt"), WithProvenance(true), WithDialect(DialectMicrosoft))
+			}
+			expected, err := parser.Parse(fix.input)
+			require.Nil(t, err)
+
+			data, err := json.Marshal(expected)
+			require.Nil(t, err)
+			var found Zone
+			err = json.Unmarshal(data, &found)
+			require.Nil(t, err)
+
+			assert.Equal(t, *expected, found)
+		}
+		closeFixtures(fixtures)
+	}
+}
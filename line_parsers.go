@@ -51,6 +51,9 @@ func parseRecordLine(line []byte) ResourceRecord {
 	return result
 }
 
+// parseSoaLine parses a SOA record line into a [ResourceRecord]. Fields that
+// are missing from the line are left empty so that the caller may determine
+// their values.
 func parseSoaLine(line []byte) ResourceRecord {
 	result := ResourceRecord{
 		Type: "SOA",
@@ -96,19 +99,19 @@ func parseSoaLine(line []byte) ResourceRecord {
 			result.Class = fields[1]
 			result.TTL = cast.ToInt(fields[2])
 		}
+		result.HasTTL = true
 		result.Values = []string{fields[4], fields[5], fields[6], fields[7], fields[8], fields[9], fields[10]}
 
 	case 10:
 		if isTtl(fields[1]) == true {
 			result.TTL = cast.ToInt(fields[1])
-			result.Class = "IN"
+			result.HasTTL = true
 		} else {
 			result.Class = fields[1]
 		}
 		result.Values = []string{fields[3], fields[4], fields[5], fields[6], fields[7], fields[8], fields[9]}
 
 	case 9:
-		result.Class = "IN"
 		result.Values = []string{fields[2], fields[3], fields[4], fields[5], fields[6], fields[7], fields[8]}
 	}
 
@@ -24,6 +24,7 @@ func Test_parseRecordLine(t *testing.T) {
 		Class:  "IN",
 		Type:   "TXT",
 		TTL:    300,
+		HasTTL: true,
 		Values: []string{"\"a=b \"", "\"c=d\""},
 	}
 	assert.Equal(t, expected, found)
@@ -52,6 +53,7 @@ func Test_parseRecordLine(t *testing.T) {
 		Class:  "IN",
 		Type:   "NS",
 		TTL:    300,
+		HasTTL: true,
 		Values: []string{"dns1"},
 	}
 	assert.Equal(t, expected, found)
@@ -61,6 +63,7 @@ func Test_parseRecordLine(t *testing.T) {
 	expected = ResourceRecord{
 		Type:   "NS",
 		TTL:    300,
+		HasTTL: true,
 		Values: []string{"dns1"},
 	}
 	assert.Equal(t, expected, found)
@@ -82,6 +85,7 @@ func Test_parseRecordLine(t *testing.T) {
 		Class:  "in",
 		Type:   "a",
 		TTL:    300,
+		HasTTL: true,
 		Values: []string{"1.1.1.1"},
 	}
 	assert.Equal(t, expected, found)
@@ -93,6 +97,7 @@ func Test_parseRecordLine(t *testing.T) {
 		Class:  "in",
 		Type:   "a",
 		TTL:    300,
+		HasTTL: true,
 		Values: []string{"1.1.1.1"},
 	}
 	assert.Equal(t, expected, found)
@@ -143,10 +148,11 @@ func Test_parseSoaLine(t *testing.T) {
 	line := []byte(" @ 300 IN SOA ns.example.com. foo.example.net. 123456 1000 1000 84000 3600")
 	record := parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
-		Name:  "@",
-		Class: "IN",
-		Type:  "SOA",
-		TTL:   300,
+		Name:   "@",
+		Class:  "IN",
+		Type:   "SOA",
+		TTL:    300,
+		HasTTL: true,
 		Values: []string{
 			"ns.example.com.",
 			"foo.example.net.",
@@ -162,10 +168,11 @@ func Test_parseSoaLine(t *testing.T) {
 	line = []byte("@ IN 300 SOA ns.example.com. foo.example.net. 123456 1000 1000 84000 3600")
 	record = parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
-		Name:  "@",
-		Class: "IN",
-		Type:  "SOA",
-		TTL:   300,
+		Name:   "@",
+		Class:  "IN",
+		Type:   "SOA",
+		TTL:    300,
+		HasTTL: true,
 		Values: []string{
 			"ns.example.com.",
 			"foo.example.net.",
@@ -181,10 +188,11 @@ func Test_parseSoaLine(t *testing.T) {
 	line = []byte(" @ 300 IN SOA ns.example.com. foo.example.net. ( 123456 1000 1000 84000 3600 )")
 	record = parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
-		Name:  "@",
-		Class: "IN",
-		Type:  "SOA",
-		TTL:   300,
+		Name:   "@",
+		Class:  "IN",
+		Type:   "SOA",
+		TTL:    300,
+		HasTTL: true,
 		Values: []string{
 			"ns.example.com.",
 			"foo.example.net.",
@@ -200,10 +208,11 @@ func Test_parseSoaLine(t *testing.T) {
 	line = []byte(" @ 300 IN SOA ns.example.com. foo.example.net. ( 123456 1000 1000 84000 3600 ) ; comment")
 	record = parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
-		Name:  "@",
-		Class: "IN",
-		Type:  "SOA",
-		TTL:   300,
+		Name:   "@",
+		Class:  "IN",
+		Type:   "SOA",
+		TTL:    300,
+		HasTTL: true,
 		Values: []string{
 			"ns.example.com.",
 			"foo.example.net.",
@@ -238,10 +247,11 @@ func Test_parseSoaLine(t *testing.T) {
 	line = []byte("@ 300 SOA ns.example.com. foo.example.net. 123456 1000 1000 84000 3600")
 	record = parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
-		Name:  "@",
-		Class: "IN",
-		Type:  "SOA",
-		TTL:   300,
+		Name:   "@",
+		Class:  "",
+		Type:   "SOA",
+		TTL:    300,
+		HasTTL: true,
 		Values: []string{
 			"ns.example.com.",
 			"foo.example.net.",
@@ -258,7 +268,7 @@ func Test_parseSoaLine(t *testing.T) {
 	record = parseSoaLine(line)
 	assert.Equal(t, record, ResourceRecord{
 		Name:  "@",
-		Class: "IN",
+		Class: "",
 		Type:  "SOA",
 		TTL:   0,
 		Values: []string{
@@ -0,0 +1,448 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks the findings of [Lint] and [Policy.Evaluate].
+type Severity int
+
+const (
+	// SeverityOff disables a rule.
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityOff:
+		return "off"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// UnmarshalText reads a severity from its name, e.g. `warning`, so that
+// severities can be given in configuration files.
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, severity := range []Severity{SeverityOff, SeverityInfo, SeverityWarning, SeverityError} {
+		if strings.EqualFold(string(text), severity.String()) {
+			*s = severity
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity: %s", text)
+}
+
+// The identifiers of the rules checked by [Lint].
+const (
+	// LintSoaTimers reports SOA timers outside the recommendations of
+	// RFC 1912 §2.2, and a negative caching TTL outside those of RFC 2308
+	// §5.
+	LintSoaTimers = "soa-timers"
+
+	// LintTtlRange reports TTLs outside the range set with
+	// [WithLintTtlRange].
+	LintTtlRange = "ttl-range"
+
+	// LintNsTtl reports apex NS records whose TTL differs from the TTL the
+	// parent zone gives the delegation, as set with [WithLintParentNsTtl].
+	LintNsTtl = "ns-ttl"
+
+	// LintHostname reports owner names, and the hosts named by MX, NS, and
+	// SRV records, that are not host names as described in RFC 952 and
+	// RFC 1123 §2.1. Owner names may hold a leading `*`, and labels that
+	// start with an underscore where the type uses them, i.e. for SRV,
+	// TLSA, URI, SVCB, and HTTPS records, and for TXT records below labels
+	// such as `_domainkey` and `_dmarc`.
+	LintHostname = "hostname"
+
+	// LintObsoleteType reports records of types that are obsolete,
+	// experimental, or were never widely deployed, e.g. MD, A6, or SPF.
+	LintObsoleteType = "obsolete-type"
+
+	// LintTrailingDot reports relative names that look like they were
+	// meant to be absolute, e.g. `ns1.example.com` in the zone
+	// `example.com.`, which is read as `ns1.example.com.example.com.`.
+	LintTrailingDot = "trailing-dot"
+
+	// LintTxtLength reports TXT and SPF strings longer than 255 bytes,
+	// which cannot be encoded.
+	LintTxtLength = "txt-length"
+)
+
+// lintSeverities holds the default severity of every rule.
+var lintSeverities = map[string]Severity{
+	LintSoaTimers:    SeverityWarning,
+	LintTtlRange:     SeverityWarning,
+	LintNsTtl:        SeverityInfo,
+	LintHostname:     SeverityWarning,
+	LintObsoleteType: SeverityWarning,
+	LintTrailingDot:  SeverityWarning,
+	LintTxtLength:    SeverityError,
+}
+
+// The comments that suppress findings, see [Lint].
+const (
+	lintIgnoreComment     = "lint:ignore"
+	lintIgnoreFileComment = "lint:ignore-file"
+)
+
+// LintRules lists the identifiers of the rules checked by [Lint] in sorted
+// order.
+func LintRules() []string {
+	result := make([]string, 0, len(lintSeverities))
+	for rule := range lintSeverities {
+		result = append(result, rule)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Finding is a problem with a record, found by [Lint] or by
+// [Policy.Evaluate]. Rule is the identifier of the lint or policy rule that
+// reported it.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Record   ResourceRecord
+	Message  string
+}
+
+// String renders the finding as `position: severity: name type: message
+// (rule)`. The position is omitted when it is unknown.
+func (f Finding) String() string {
+	description := fmt.Sprintf("%s: %s %s: %s (%s)", f.Severity, f.Record.Name, f.Record.Type, f.Message, f.Rule)
+	if f.Record.Position.IsValid() {
+		return f.Record.Position.String() + ": " + description
+	}
+	return description
+}
+
+type LintOption func(l *linter) error
+
+type linter struct {
+	severities  map[string]Severity
+	minTtl      int
+	maxTtl      int
+	parentNsTtl int
+	origin      string
+}
+
+// WithLintSeverity sets the severity of a rule. Rules are disabled with
+// [SeverityOff].
+func WithLintSeverity(rule string, severity Severity) LintOption {
+	return func(l *linter) error {
+		if _, ok := lintSeverities[rule]; ok == false {
+			return fmt.Errorf("unknown lint rule: %s", rule)
+		}
+		if severity < SeverityOff || severity > SeverityError {
+			return fmt.Errorf("unknown severity: %d", severity)
+		}
+		l.severities[rule] = severity
+		return nil
+	}
+}
+
+// WithLintTtlRange sets the lowest and highest TTL that [LintTtlRange]
+// accepts. The defaults are `300` and `604_800`.
+func WithLintTtlRange(min int, max int) LintOption {
+	return func(l *linter) error {
+		if min < 0 || max < min {
+			return fmt.Errorf("invalid ttl range: %d to %d", min, max)
+		}
+		l.minTtl = min
+		l.maxTtl = max
+		return nil
+	}
+}
+
+// WithLintParentNsTtl sets the TTL of the delegation in the parent zone,
+// which [LintNsTtl] compares the apex NS records with. The default is
+// `172_800`, as used by most top level domains.
+func WithLintParentNsTtl(value int) LintOption {
+	return func(l *linter) error {
+		if value < 0 {
+			return fmt.Errorf("ttl must not be negative: %d", value)
+		}
+		l.parentNsTtl = value
+		return nil
+	}
+}
+
+// WithLintOrigin sets the origin of the zone. By default, the name of the
+// SOA record is used when it is absolute. The rules that concern the apex,
+// or relative names, are skipped when the origin is unknown.
+func WithLintOrigin(origin string) LintOption {
+	return func(l *linter) error {
+		if origin == "" {
+			return errors.New("origin must not be empty")
+		}
+		l.origin = fqdn(origin)
+		return nil
+	}
+}
+
+// Lint checks a zone for records that are valid, but are likely mistakes or
+// are not recommended. The rules, and their default severities, are
+// described by the Lint constants, e.g. [LintTtlRange]. Findings are
+// returned in the order of the records, starting with the SOA record.
+//
+// Findings can be suppressed with comments, which the parser keeps when it
+// is created with [WithComments]. A `; lint:ignore` comment on the lines of
+// a record, or directly before it, suppresses the findings of that record,
+// and a `; lint:ignore-file` comment anywhere in the file suppresses them
+// for every record. Both apply to all rules, or to a comma separated list of
+// rules, optionally followed by a reason:
+//
+//	; lint:ignore-file obsolete-type
+//	legacy 60 IN A 192.0.2.1 ; lint:ignore ttl-range,hostname monitoring
+func Lint(z *Zone, opts ...LintOption) ([]Finding, error) {
+	l := &linter{
+		severities:  make(map[string]Severity),
+		minTtl:      300,
+		maxTtl:      604800,
+		parentNsTtl: 172800,
+	}
+	for rule, severity := range lintSeverities {
+		l.severities[rule] = severity
+	}
+	for _, opt := range opts {
+		err := opt(l)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if l.origin == "" && isAbsoluteName(z.SOA.Name) {
+		l.origin = z.SOA.Name
+	}
+
+	records := z.AllRecords()
+
+	fileIgnores := make(map[string]bool)
+	for _, comment := range z.Comments {
+		parseLintIgnore(comment, lintIgnoreFileComment, fileIgnores)
+	}
+	for _, rr := range records {
+		for _, comment := range rr.Comments {
+			parseLintIgnore(comment, lintIgnoreFileComment, fileIgnores)
+		}
+	}
+
+	result := make([]Finding, 0)
+	for _, rr := range records {
+		ignores := make(map[string]bool)
+		for _, comment := range rr.Comments {
+			parseLintIgnore(comment, lintIgnoreComment, ignores)
+		}
+		report := func(rule string, format string, args ...any) {
+			severity := l.severities[rule]
+			if severity == SeverityOff || fileIgnores[""] || fileIgnores[rule] || ignores[""] || ignores[rule] {
+				return
+			}
+			result = append(result, Finding{
+				Rule:     rule,
+				Severity: severity,
+				Record:   rr,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+		l.lintRecord(rr, report)
+	}
+	return result, nil
+}
+
+// parseLintIgnore adds the rules that a suppression comment names to
+// ignores. All rules are represented by the empty string. Comments that are
+// not suppressions of the given kind are skipped.
+func parseLintIgnore(comment string, kind string, ignores map[string]bool) {
+	fields := strings.Fields(comment)
+	if len(fields) == 0 || fields[0] != kind {
+		return
+	}
+	if len(fields) == 1 {
+		ignores[""] = true
+		return
+	}
+	for _, rule := range strings.Split(fields[1], ",") {
+		if rule != "" {
+			ignores[rule] = true
+		}
+	}
+}
+
+// lintRecord checks a record with every rule.
+func (l *linter) lintRecord(rr ResourceRecord, report func(rule string, format string, args ...any)) {
+	recordType := strings.ToUpper(rr.Type)
+
+	if recordType == "SOA" {
+		l.lintSoaTimers(rr, report)
+	}
+
+	if rr.TTL < l.minTtl {
+		report(LintTtlRange, "ttl %d is below %d", rr.TTL, l.minTtl)
+	} else if rr.TTL > l.maxTtl {
+		report(LintTtlRange, "ttl %d is above %d", rr.TTL, l.maxTtl)
+	}
+
+	if recordType == "NS" && l.origin != "" && strings.EqualFold(qualifyName(rr.Name, l.origin), l.origin) && rr.TTL != l.parentNsTtl {
+		report(LintNsTtl, "ttl %d differs from the ttl %d of the delegation", rr.TTL, l.parentNsTtl)
+	}
+
+	if label, ok := invalidHostLabel(rr.Name, recordType); ok == false {
+		report(LintHostname, "owner name has the invalid label %q", label)
+	}
+	schema, hasSchema := rdataSchema(recordType)
+	generic := isGenericRdata(rr.Values)
+	if hasSchema == true && generic == false && (recordType == "MX" || recordType == "NS" || recordType == "SRV") {
+		for i, v := range rr.Values {
+			field, ok := rdataFieldAt(schema, i)
+			if ok == false || field.kind.isName() == false {
+				continue
+			}
+			if label, ok := invalidHostLabel(v, ""); ok == false {
+				report(LintHostname, "%s has the invalid label %q", field.name, label)
+			}
+		}
+	}
+
+	if slices.Contains(obscureRecordTypes, recordType) && slices.Contains(commonRecordTypes, recordType) == false {
+		report(LintObsoleteType, "type %s is obsolete or experimental", recordType)
+	}
+
+	if l.origin != "" {
+		if l.looksAbsolute(rr.Name) {
+			report(LintTrailingDot, "owner name %s is missing a trailing dot", rr.Name)
+		}
+		if hasSchema == true && generic == false {
+			for i, v := range rr.Values {
+				field, ok := rdataFieldAt(schema, i)
+				if ok == true && field.kind.isName() && l.looksAbsolute(v) {
+					report(LintTrailingDot, "%s %s is missing a trailing dot", field.name, v)
+				}
+			}
+		}
+	}
+
+	if isTextType(recordType) && generic == false {
+		for _, v := range rr.Values {
+			str, err := parseCharacterString(v)
+			if err == nil && len(str) > maxCharacterStringLength {
+				report(LintTxtLength, "string of %d bytes exceeds %d bytes", len(str), maxCharacterStringLength)
+			}
+		}
+	}
+}
+
+// lintSoaTimers checks the timers of a SOA record. Values that are not
+// numbers are left to [ResourceRecord.Validate].
+func (l *linter) lintSoaTimers(rr ResourceRecord, report func(rule string, format string, args ...any)) {
+	if len(rr.Values) != 7 {
+		return
+	}
+	timers := make([]int, 4)
+	for i, v := range rr.Values[3:] {
+		value, err := strconv.Atoi(v)
+		if err != nil {
+			return
+		}
+		timers[i] = value
+	}
+	refresh, retry, expire, minimum := timers[0], timers[1], timers[2], timers[3]
+	if refresh < 1200 || refresh > 43200 {
+		report(LintSoaTimers, "refresh %d is outside 1200 to 43200", refresh)
+	}
+	if retry < 180 || retry >= refresh {
+		report(LintSoaTimers, "retry %d is not between 180 and the refresh %d", retry, refresh)
+	}
+	if expire < 1209600 || expire > 2419200 {
+		report(LintSoaTimers, "expire %d is outside 1209600 to 2419200", expire)
+	}
+	if minimum < 300 || minimum > 86400 {
+		report(LintSoaTimers, "minimum %d is outside 300 to 86400", minimum)
+	}
+}
+
+// looksAbsolute determines if a name ends with the origin, but lacks the
+// trailing dot that would make it absolute. Absolute names that hold the
+// origin twice, as such names become once qualified, are included.
+func (l *linter) looksAbsolute(name string) bool {
+	if l.origin == "." || name == "@" || name == "" {
+		return false
+	}
+	name = strings.ToLower(name)
+	bare := strings.ToLower(strings.TrimSuffix(l.origin, "."))
+	if isAbsoluteName(name) {
+		doubled := bare + "." + strings.ToLower(l.origin)
+		return name == doubled || strings.HasSuffix(name, "."+doubled)
+	}
+	return name == bare || strings.HasSuffix(name, "."+bare)
+}
+
+// underscoreTypes lists the types whose owner names hold labels that start
+// with an underscore, e.g. `_sip._tcp` of SRV records, or `_443._tcp` of
+// TLSA records.
+var underscoreTypes = []string{"HTTPS", "SRV", "SVCB", "TLSA", "URI"}
+
+// underscoreTxtLabels lists the underscore labels, from the registry of RFC
+// 8552, below which TXT records are found, e.g. DKIM keys at
+// `selector._domainkey`.
+var underscoreTxtLabels = []string{"_acme-challenge", "_dmarc", "_domainkey", "_mta-sts", "_tls"}
+
+// invalidHostLabel finds the first label of a name that is not a host name
+// label of letters, digits, and hyphens that neither starts nor ends with a
+// hyphen. Owner names, whose type is given as ownerType, may also start with
+// `*`, and hold labels that start with an underscore when the type uses
+// them, as listed by underscoreTypes and underscoreTxtLabels. The second
+// return value is false when such a label is found.
+func invalidHostLabel(name string, ownerType string) (string, bool) {
+	if name == "" || name == "@" || name == "." {
+		return "", true
+	}
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+	underscores := slices.Contains(underscoreTypes, ownerType)
+	if ownerType == "TXT" {
+		for _, label := range labels {
+			underscores = underscores || slices.Contains(underscoreTxtLabels, strings.ToLower(label))
+		}
+	}
+	for i, label := range labels {
+		if ownerType != "" && i == 0 && label == "*" {
+			continue
+		}
+		if underscores == true && strings.HasPrefix(label, "_") {
+			continue
+		}
+		if isHostLabel(label) == false {
+			return label, false
+		}
+	}
+	return "", true
+}
+
+// isHostLabel determines if a label follows the LDH rule of RFC 952, as
+// relaxed by RFC 1123 §2.1 to allow a leading digit.
+func isHostLabel(label string) bool {
+	if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		b := label[i]
+		if (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || isDigitByte(b) || b == '-' {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_Lint_Fixtures(t *testing.T) {
+	zp, _ := NewZoneParser(WithComments(true))
+	fixtures, err := readFixtures("testdata/lint")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	for name, fix := range fixtures {
+		t.Logf("testing fixture: %s", name)
+		z, err := zp.Parse(fix.input)
+		require.Nil(t, err)
+		findings, err := Lint(z)
+		require.Nil(t, err)
+		found := strings.Builder{}
+		for _, finding := range findings {
+			found.WriteString(finding.String() + "\n")
+		}
+		assert.Equal(t, fix.expected, found.String())
+	}
+}
+
+func Test_Lint(t *testing.T) {
+	input := "$ORIGIN example.com.\n" +
+		"@ 60 IN NS ns1.example.com.\n" +
+		"www_1 60 IN CNAME web.example.com ; lint:ignore hostname\n"
+	z := mustParse(t, input, WithComments(true))
+
+	findings, err := Lint(z, WithLintOrigin("example.com"))
+	require.Nil(t, err)
+	require.Len(t, findings, 4)
+	assert.Equal(t, LintTtlRange, findings[0].Rule)
+	assert.Equal(t, LintNsTtl, findings[1].Rule)
+	assert.Equal(t, SeverityInfo, findings[1].Severity)
+	assert.Equal(t, LintTtlRange, findings[2].Rule)
+	assert.Equal(t, "3: warning: www_1.example.com. CNAME: target web.example.com is missing a trailing dot (trailing-dot)", findings[3].String())
+
+	findings, err = Lint(z,
+		WithLintOrigin("example.com"),
+		WithLintSeverity(LintTrailingDot, SeverityError),
+		WithLintSeverity(LintNsTtl, SeverityOff),
+		WithLintTtlRange(60, 3600),
+	)
+	require.Nil(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+
+	// Without the parsed comments, nothing is suppressed, and the origin is
+	// only known when given.
+	z = mustParse(t, "www 600 IN MX 10 mail.example.com\n")
+	findings, err = Lint(z)
+	require.Nil(t, err)
+	assert.Len(t, findings, 0)
+	findings, err = Lint(z, WithLintOrigin("example.com"))
+	require.Nil(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, LintTrailingDot, findings[0].Rule)
+
+	_, err = Lint(z, WithLintSeverity("no-such-rule", SeverityError))
+	assert.ErrorContains(t, err, "unknown lint rule: no-such-rule")
+	_, err = Lint(z, WithLintTtlRange(600, 60))
+	assert.ErrorContains(t, err, "invalid ttl range")
+}
+
+func Test_invalidHostLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		ownerType string
+		expected  string
+	}{
+		{name: "www.example.com.", expected: ""},
+		{name: "1host.example.com.", expected: ""},
+		{name: "-host.example.com.", expected: "-host"},
+		{name: "host-.example.com", expected: "host-"},
+		{name: "my_host", expected: "my_host"},
+		{name: "_sip._tcp.example.com.", ownerType: "SRV", expected: ""},
+		{name: "_sip._tcp.example.com.", expected: "_sip"},
+		{name: "_443._tcp.www", ownerType: "TLSA", expected: ""},
+		{name: "_foo", ownerType: "A", expected: "_foo"},
+		{name: "_foo", ownerType: "TXT", expected: "_foo"},
+		{name: "s1._domainkey", ownerType: "TXT", expected: ""},
+		{name: "_dmarc.example.com.", ownerType: "TXT", expected: ""},
+		{name: "_acme-challenge.www", ownerType: "TXT", expected: ""},
+		{name: "s1._domainkey", ownerType: "A", expected: "_domainkey"},
+		{name: "*.example.com.", ownerType: "A", expected: ""},
+		{name: "*.example.com.", expected: "*"},
+		{name: "a.*.example.com.", ownerType: "A", expected: "*"},
+		{name: ".", expected: ""},
+	}
+	for _, test := range tests {
+		label, ok := invalidHostLabel(test.name, test.ownerType)
+		assert.Equal(t, test.expected, label, test.name)
+		assert.Equal(t, test.expected == "", ok, test.name)
+	}
+}
+
+func Test_Severity_String(t *testing.T) {
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "severity(9)", Severity(9).String())
+}
@@ -0,0 +1,165 @@
+package zone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LiveDNSRecord is a record set in the shape used by the [Gandi LiveDNS]
+// API, e.g. the items of `GET /livedns/domains/{fqdn}/records`.
+//
+// [Gandi LiveDNS]: https://api.gandi.net/docs/livedns/
+type LiveDNSRecord struct {
+	// Name is relative to the domain, with `@` denoting the domain itself.
+	Name string `json:"rrset_name"`
+	Type string `json:"rrset_type"`
+
+	// TTL is nil when the TTL is not given, in which case LiveDNS applies
+	// its default. LiveDNS rejects TTLs below 300 seconds.
+	TTL    *int     `json:"rrset_ttl,omitempty"`
+	Values []string `json:"rrset_values"`
+}
+
+// liveDNSMinTtl is the smallest TTL, in seconds, that LiveDNS accepts.
+const liveDNSMinTtl = 300
+
+// liveDNSTypes lists the record types that LiveDNS supports.
+var liveDNSTypes = []string{
+	"A", "AAAA", "ALIAS", "CAA", "CDS", "CNAME", "DNAME", "DS", "HTTPS", "KEY",
+	"LOC", "MX", "NAPTR", "NS", "OPENPGPKEY", "PTR", "RP", "SPF", "SRV", "SSHFP",
+	"SVCB", "TLSA", "TXT", "WKS",
+}
+
+// LiveDNSRecords converts the records of a zone into LiveDNS record sets for
+// the domain at apex. Relative owner names are taken to be relative to apex.
+// The SOA record is omitted because LiveDNS manages it.
+//
+// Records whose owner names are outside apex are rejected, wrapping
+// [ErrOutOfZone], as are records of a class other than IN, or with a TTL
+// below the 300 seconds LiveDNS accepts, wrapping [ErrUnsupportedRecord].
+// Every rejected record is reported as a [RecordError] in the returned
+// error.
+func LiveDNSRecords(z *Zone, apex string) ([]LiveDNSRecord, error) {
+	apex = fqdn(apex)
+
+	var errs []error
+	records := make([]ResourceRecord, 0, len(z.Records))
+	for _, rr := range z.Records {
+		name, ok := relativeName(rr.Name, apex)
+		if ok == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrOutOfZone})
+			continue
+		}
+		if strings.EqualFold(rr.Class, defaultClass) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrUnsupportedRecord})
+			continue
+		}
+		if hasExplicitTtl([]ResourceRecord{rr}) && rr.TTL < liveDNSMinTtl {
+			err := fmt.Errorf("ttl %d is below the minimum of %d: %w", rr.TTL, liveDNSMinTtl, ErrUnsupportedRecord)
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+
+		value, err := liveDNSValue(rr)
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+		rr.Name = name
+		rr.Values = []string{value}
+		records = append(records, rr)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	result := make([]LiveDNSRecord, 0)
+	for _, set := range groupRRSets(records) {
+		values := make([]string, 0, len(set.Records))
+		for _, rr := range set.Records {
+			values = append(values, rr.Values[0])
+		}
+		record := LiveDNSRecord{
+			Name:   set.Name,
+			Type:   strings.ToUpper(set.Type),
+			Values: values,
+		}
+		// The set takes the smallest of the TTLs that are given, as records
+		// without one would lower it below what LiveDNS accepts.
+		for _, rr := range set.Records {
+			if hasExplicitTtl([]ResourceRecord{rr}) && (record.TTL == nil || rr.TTL < *record.TTL) {
+				ttl := rr.TTL
+				record.TTL = &ttl
+			}
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+// ParseLiveDNS reads a LiveDNS API response listing record sets, i.e. the
+// result of `GET /livedns/domains/{fqdn}/records`, into a [Zone]. Owner names
+// are qualified with apex so that the result may be compared with a zone
+// parsed from a file that sets `$ORIGIN`. Record sets without a TTL give
+// records without one, see [ResourceRecord.HasTTL].
+func ParseLiveDNS(reader io.Reader, apex string) (*Zone, error) {
+	var sets []LiveDNSRecord
+	err := json.NewDecoder(reader).Decode(&sets)
+	if err != nil {
+		return nil, fmt.Errorf("parse LiveDNS records: %w", err)
+	}
+
+	apex = fqdn(apex)
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	for _, set := range sets {
+		for _, value := range set.Values {
+			rr := ResourceRecord{
+				Name:  qualifyName(set.Name, apex),
+				Class: defaultClass,
+				Type:  set.Type,
+			}
+			if set.TTL != nil {
+				rr.TTL, rr.HasTTL = *set.TTL, true
+			}
+			for _, token := range tokenizeLine([]byte(value)) {
+				rr.Values = append(rr.Values, string(token))
+			}
+			result.Records = append(result.Records, rr)
+		}
+	}
+	return result, nil
+}
+
+// liveDNSValue renders the values of a record as a single LiveDNS value.
+// LiveDNS expects every string of a TXT value to be quoted, so unquoted
+// strings are quoted.
+func liveDNSValue(rr ResourceRecord) (string, error) {
+	if isTextType(rr.Type) == false {
+		return strings.Join(rr.Values, " "), nil
+	}
+
+	strs, err := parseCharacterStrings(rr.Values)
+	if err != nil {
+		return "", err
+	}
+	for i, str := range strs {
+		strs[i] = quoteCharacterString(str)
+	}
+	return strings.Join(strs, " "), nil
+}
+
+// hasExplicitTtl determines if any record of a set has a TTL, see
+// [ResourceRecord.HasTTL].
+func hasExplicitTtl(records []ResourceRecord) bool {
+	for _, rr := range records {
+		if rr.HasTTL == true || rr.TTL > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package zone
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_LiveDNSRecords(t *testing.T) {
+	z := mustParse(t, strings.Join([]string{
+		"$ORIGIN example.com.",
+		"@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300",
+		"@ 600 IN MX 10 mail",
+		"@ 300 IN MX 20 mail2.example.com.",
+		"www 300 IN A 192.0.2.1",
+		"www 300 IN A 192.0.2.2",
+		"@ 300 IN TXT \"v=spf1 -all\"",
+		"_dmarc 300 IN TXT \"v=DMARC1; p=reject\"",
+		"long 86400 IN A 192.0.2.3",
+		"",
+	}, "\n"))
+
+	found, err := LiveDNSRecords(z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, []LiveDNSRecord{
+		{Name: "@", Type: "MX", TTL: ttl(300), Values: []string{"10 mail", "20 mail2.example.com."}},
+		{Name: "www", Type: "A", TTL: ttl(300), Values: []string{"192.0.2.1", "192.0.2.2"}},
+		{Name: "@", Type: "TXT", TTL: ttl(300), Values: []string{`"v=spf1 -all"`}},
+		{Name: "_dmarc", Type: "TXT", TTL: ttl(300), Values: []string{`"v=DMARC1; p=reject"`}},
+		{Name: "long", Type: "A", TTL: ttl(86400), Values: []string{"192.0.2.3"}},
+	}, found)
+
+	data, err := json.Marshal(found[2])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"rrset_name":"@","rrset_type":"TXT","rrset_ttl":300,"rrset_values":["\"v=spf1 -all\""]}`, string(data))
+
+	z = &Zone{Records: []ResourceRecord{{Name: "www.example.com.", Class: "IN", Type: "A", Values: []string{"192.0.2.1"}}}}
+	found, err = LiveDNSRecords(z, "example.com")
+	require.Nil(t, err)
+	data, err = json.Marshal(found[0])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"rrset_name":"www","rrset_type":"A","rrset_values":["192.0.2.1"]}`, string(data))
+
+	// Records without a TTL do not lower the TTL of their set.
+	z.Records = append(z.Records, ResourceRecord{Name: "www.example.com.", Class: "IN", Type: "A", TTL: 600, HasTTL: true, Values: []string{"192.0.2.2"}})
+	found, err = LiveDNSRecords(z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, ttl(600), found[0].TTL)
+}
+
+func Test_LiveDNSRecords_Fixture(t *testing.T) {
+	fixtures, err := readFixtures("testdata")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	zp, _ := NewZoneParser()
+	z, err := zp.Parse(fixtures["opendkim_1024.sample.txt"].input)
+	require.Nil(t, err)
+
+	found, err := LiveDNSRecords(z, "example.com.")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "default._domainkey", found[0].Name)
+	assert.Equal(t, ttl(86400), found[0].TTL)
+	assert.True(t, strings.HasPrefix(found[0].Values[0], `"v=DKIM1; k=rsa; " "p=MIGfMA0G`))
+}
+
+func Test_LiveDNSRecords_OutOfZone(t *testing.T) {
+	z := mustParse(t, "www.example.com. 300 IN A 192.0.2.1\nwww.example.net. 300 IN A 192.0.2.2\n")
+
+	found, err := LiveDNSRecords(z, "example.com.")
+	assert.Nil(t, found)
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.ErrorContains(t, err, "2: www.example.net. A")
+}
+
+func Test_LiveDNSRecords_Unsupported(t *testing.T) {
+	z := mustParse(t, strings.Join([]string{
+		"$ORIGIN example.com.",
+		"www 300 IN A 192.0.2.1",
+		"short 60 IN A 192.0.2.2",
+		"zero 0 IN A 192.0.2.3",
+		"version 300 CH TXT \"1.0\"",
+		"",
+	}, "\n"))
+
+	found, err := LiveDNSRecords(z, "example.com.")
+	assert.Nil(t, found)
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.ErrorContains(t, err, "3: short.example.com. A: ttl 60 is below the minimum of 300")
+	assert.ErrorContains(t, err, "4: zero.example.com. A: ttl 0 is below the minimum of 300")
+	assert.ErrorContains(t, err, "5: version.example.com. TXT: record is not supported")
+}
+
+func Test_ParseLiveDNS(t *testing.T) {
+	response := `[
+		{"rrset_name": "@", "rrset_type": "TXT", "rrset_ttl": 10800, "rrset_values": ["\"v=spf1 include:_mailcust.gandi.net ?all\""],
+		 "rrset_href": "https://api.gandi.net/v5/livedns/domains/example.com/records/%40/TXT"},
+		{"rrset_name": "www", "rrset_type": "CNAME", "rrset_ttl": 10800, "rrset_values": ["webredir.vip.gandi.net."]},
+		{"rrset_name": "api", "rrset_type": "A", "rrset_values": ["192.0.2.1"]}
+	]`
+	found, err := ParseLiveDNS(strings.NewReader(response), "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, []ResourceRecord{
+		{
+			Name:   "example.com.",
+			Class:  "IN",
+			Type:   "TXT",
+			TTL:    10800,
+			HasTTL: true,
+			Values: []string{`"v=spf1 include:_mailcust.gandi.net ?all"`},
+		},
+		{
+			Name:   "www.example.com.",
+			Class:  "IN",
+			Type:   "CNAME",
+			TTL:    10800,
+			HasTTL: true,
+			Values: []string{"webredir.vip.gandi.net."},
+		},
+		{
+			Name:   "api.example.com.",
+			Class:  "IN",
+			Type:   "A",
+			Values: []string{"192.0.2.1"},
+		},
+	}, found.Records)
+
+	_, err = ParseLiveDNS(strings.NewReader("{"), "example.com")
+	assert.ErrorContains(t, err, "parse LiveDNS records")
+}
+
+func ttl(value int) *int {
+	return &value
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cast"
 	"io"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -17,6 +18,7 @@ const commentStartByte = byte(';')
 const escapeByte = byte('\\')
 const quoteByte = byte('"')
 const defaultTtl = 86400
+const defaultClass = "IN"
 
 var commentStartBytes = []byte{commentStartByte}
 var originLineBytes = []byte("$ORIGIN")
@@ -29,16 +31,34 @@ var generateLineBytes = []byte("$GENERATE")
 //
 // It also recognizes the QCLASS "any" defined in
 // https://datatracker.ietf.org/doc/html/rfc1035#section-3.2.5
-var isClassToken = regexp.MustCompile(`in|IN|ch|CH|hs|HS|cs|CS|any|ANY`)
+//
+// The whole token must be a class, so that owner names like
+// `default._domainkey` are not mistaken for one.
+var isClassToken = regexp.MustCompile(`^(?i:in|ch|hs|cs|any)$`)
 var isTtlToken = regexp.MustCompile(`^[0-9]+$`)
 var isSoaLine = regexp.MustCompile(`\s+(SOA|soa)\s+`)
 
+// digSectionLine matches the section headers of dig output, e.g.
+// `;; ANSWER SECTION:`.
+var digSectionLine = regexp.MustCompile(`^;; ([A-Z]+) SECTION:`)
+
+// digOptRecord matches the OPT pseudo-record of dig output, e.g.
+// `. 0 CLASS1232 OPT`, whose class is the UDP payload size in the RFC 3597
+// `CLASSnnn` form.
+var digOptRecord = regexp.MustCompile(`^\S+\s+[0-9]+\s+(?i:class[0-9]+)\s+(?i:opt)(\s|$)`)
+
 // ZoneParser reads zone [master files] into [Zone] objects.
 //
 // [master files]: https://datatracker.ietf.org/doc/html/rfc1035#autoid-48
 type ZoneParser struct {
+	comments        bool
 	defaultTtl      int
+	dialect         Dialect
+	digOutput       bool
+	fileName        string
+	origin          string
 	preferSoaMinTtl bool
+	provenance      bool
 	skipIncludes    bool
 }
 
@@ -60,6 +80,18 @@ func NewZoneParser(opts ...Option) (*ZoneParser, error) {
 	return zoneParser, nil
 }
 
+// WithComments will keep the comments of the input when value is `true`.
+// Comments on the lines of a record, and on the comment lines directly
+// before it, are kept in [ResourceRecord.Comments]. Other comment lines, e.g.
+// those followed by a blank line or a directive, are kept in
+// [Zone.Comments].
+func WithComments(value bool) Option {
+	return func(zp *ZoneParser) error {
+		zp.comments = value
+		return nil
+	}
+}
+
 // WithDefaultTtl allows defining the default TTL that will be used when no
 // $TTL directive has been found. The default is `86_400`.
 // If `WithPreferSoaMinTtl(true)` is used, and a SOA record is present, then
@@ -71,6 +103,63 @@ func WithDefaultTtl(value int) Option {
 	}
 }
 
+// WithDialect sets the variant of the zone file format to read. The
+// default is [DialectRFC1035].
+func WithDialect(dialect Dialect) Option {
+	return func(zp *ZoneParser) error {
+		if dialect != DialectRFC1035 && dialect != DialectMicrosoft {
+			return fmt.Errorf("unknown dialect: %d", dialect)
+		}
+		zp.dialect = dialect
+		return nil
+	}
+}
+
+// WithDigOutput will read the input as the output of `dig` when value is
+// `true`, e.g. as captured from `dig axfr example.com` or `dig +multi`.
+// Records of the ANSWER, AUTHORITY, and ADDITIONAL sections are kept, while
+// the QUESTION section and OPT pseudo-records are skipped. The first SOA
+// record becomes the zone's SOA, and repeats of it, such as the SOA record
+// that ends a zone transfer, are discarded.
+func WithDigOutput(value bool) Option {
+	return func(zp *ZoneParser) error {
+		zp.digOutput = value
+		return nil
+	}
+}
+
+// WithFileName sets the file name that is recorded in the [Position] of
+// every parsed record. The parser does not read the named file.
+func WithFileName(name string) Option {
+	return func(zp *ZoneParser) error {
+		zp.fileName = name
+		return nil
+	}
+}
+
+// WithOrigin sets the origin that relative names are qualified with until
+// the input sets one with an `$ORIGIN` directive. By default, relative names
+// are kept as given until then.
+func WithOrigin(origin string) Option {
+	return func(zp *ZoneParser) error {
+		if origin == "" {
+			return errors.New("origin must not be empty")
+		}
+		zp.origin = fqdn(origin)
+		return nil
+	}
+}
+
+// WithProvenance will record a [Provenance] on every parsed record when
+// value is `true`. It describes how the parser derived the record's owner
+// name, TTL, and class.
+func WithProvenance(value bool) Option {
+	return func(zp *ZoneParser) error {
+		zp.provenance = value
+		return nil
+	}
+}
+
 // WithPreferSoaMinTtl will _always_ use the minimum TTL value from the SOA
 // line when value is `true`. Any `$TTL` directives will be ignored.
 func WithPreferSoaMinTtl(value bool) Option {
@@ -91,16 +180,33 @@ func WithSkipIncludes(value bool) Option {
 }
 
 // Parse reads the given reader line-by-line as a zone file.
-// All comments are discarded.
+// All comments are discarded unless [WithComments] is used.
+//
+// Records that omit a TTL receive the TTL from the most recent `$TTL`
+// directive, or the default TTL. An explicit TTL of `0` is preserved.
+// Records that omit a class inherit the class of the previous record, per
+// RFC 1035 §5.1, falling back to `IN` for the first record.
 func (zp *ZoneParser) Parse(reader io.Reader) (*Zone, error) {
 	result := &Zone{
 		Records: make([]ResourceRecord, 0),
 	}
 
 	r := bufio.NewReader(reader)
-	var currentOrigin string
+	currentOrigin := zp.origin
 	var currentTtl int
+	var currentTtlSource ValueSource
 	var lastRecord ResourceRecord
+	var digSection string
+	var comments []string
+	// keepComments moves the comment lines that have not been attached to a
+	// record to the zone.
+	keepComments := func() {
+		if len(comments) > 0 {
+			result.Comments = append(result.Comments, comments...)
+			comments = nil
+		}
+	}
+	lineNumber := 0
 	for {
 		line, err := r.ReadBytes('\n')
 		if err != nil {
@@ -109,34 +215,88 @@ func (zp *ZoneParser) Parse(reader io.Reader) (*Zone, error) {
 			}
 			return nil, err
 		}
+		lineNumber += 1
+		position := Position{
+			File:      zp.fileName,
+			StartLine: lineNumber,
+			EndLine:   lineNumber,
+		}
 
-		if bytes.Equal(line, []byte("\n")) ||
-			bytes.HasPrefix(line, commentStartBytes) ||
-			bytes.HasPrefix(line, generateLineBytes) {
+		if zp.digOutput == true {
+			if matches := digSectionLine.FindSubmatch(line); matches != nil {
+				digSection = string(matches[1])
+				continue
+			}
+			if digSection == "QUESTION" && bytes.HasPrefix(line, commentStartBytes) == false {
+				continue
+			}
+			if digOptRecord.Match(line) {
+				continue
+			}
+		}
+
+		// Lines that only hold white space, e.g. the `\r\n` of files
+		// written on Windows, or an indented comment, are blank.
+		if len(bytes.TrimSpace(stripComment(line))) == 0 ||
+			bytes.HasPrefix(line, commentStartBytes) {
+			if comment, ok := lineComment(line); ok == true && zp.comments == true {
+				comments = append(comments, comment)
+			} else {
+				keepComments()
+			}
+			continue
+		}
+		if bytes.HasPrefix(line, generateLineBytes) {
+			keepComments()
 			continue
 		}
 
+		recordComments := comments
+		comments = nil
+		if comment, ok := lineComment(line); ok == true && zp.comments == true {
+			recordComments = append(recordComments, comment)
+		}
 		if isContinuedLine(line) {
-			line, err = readContinuedLine(r, line)
+			var count int
+			var continuedComments []string
+			line, continuedComments, count, err = readContinuedLine(r, line)
 			if err != nil {
 				return nil, err
 			}
+			lineNumber += count
+			position.EndLine = lineNumber
+			if zp.comments == true {
+				recordComments = append(recordComments, continuedComments...)
+			}
 		}
 
-		if bytes.Equal(line[0:7], originLineBytes) {
+		var metadata map[string]string
+		if zp.dialect == DialectMicrosoft {
+			line, metadata = parseMicrosoftAge(line)
+		}
+
+		if bytes.HasPrefix(line, originLineBytes) ||
+			bytes.HasPrefix(line, ttlLineBytes) ||
+			bytes.HasPrefix(line, includeLineBytes) {
+			comments = recordComments
+			keepComments()
+		}
+
+		if bytes.HasPrefix(line, originLineBytes) {
 			currentOrigin = parseOriginLine(line)
 			continue
 		}
 
-		if bytes.Equal(line[0:4], ttlLineBytes) {
+		if bytes.HasPrefix(line, ttlLineBytes) {
 			if zp.preferSoaMinTtl == true {
 				continue
 			}
 			currentTtl = parseTtlLine(line)
+			currentTtlSource = SourceTtlDirective
 			continue
 		}
 
-		if bytes.Equal(line[0:8], includeLineBytes) {
+		if bytes.HasPrefix(line, includeLineBytes) {
 			if zp.skipIncludes == true {
 				continue
 			}
@@ -145,49 +305,143 @@ func (zp *ZoneParser) Parse(reader io.Reader) (*Zone, error) {
 
 		if isSoaLine.Match(line) == true {
 			record := parseSoaLine(line)
-			if record.Name == "@" && currentOrigin != "" {
-				record.Name = currentOrigin
+			record.Position = position
+			record.Metadata = metadata
+			record.Comments = recordComments
+			provenance := Provenance{
+				Name:   SourceLine,
+				TTL:    SourceLine,
+				Class:  SourceLine,
+				Origin: currentOrigin,
 			}
-			if record.TTL == 0 {
-				if zp.preferSoaMinTtl {
+			if record.Name == "@" {
+				provenance.Name = SourceAbsent
+				if currentOrigin != "" {
+					record.Name = currentOrigin
+					provenance.Name = SourceOrigin
+				}
+			}
+			if record.Class == "" {
+				record.Class, provenance.Class = inheritClass(lastRecord)
+			}
+			if record.HasTTL == false {
+				soaMinTtl := zp.preferSoaMinTtl ||
+					(zp.dialect == DialectMicrosoft && currentTtlSource == SourceAbsent)
+				if soaMinTtl == true {
 					minTtl := cast.ToInt(record.Values[len(record.Values)-1])
 					record.TTL = minTtl
 					currentTtl = minTtl
-				} else if currentTtl > 0 {
+					currentTtlSource = SourceSoaMinimum
+					provenance.TTL = SourceSoaMinimum
+				} else if currentTtlSource != SourceAbsent {
 					record.TTL = currentTtl
+					provenance.TTL = currentTtlSource
 				} else {
-					record.TTL = defaultTtl
+					record.TTL = zp.defaultTtl
+					provenance.TTL = SourceDefault
 				}
+				record.HasTTL = true
+			}
+			if zp.provenance == true {
+				record.Provenance = &provenance
 			}
-			result.SOA = record
 			lastRecord = record
+			if zp.digOutput == true && result.SOA.IsEmpty() == false {
+				if isSameRecord(result.SOA, record) == false {
+					result.Records = append(result.Records, record)
+				}
+				continue
+			}
+			result.SOA = record
 			continue
 		}
 
 		record := parseRecordLine(line)
+		if zp.dialect == DialectMicrosoft {
+			if microsoftRecord, ok := parseMicrosoftRecordLine(line); ok == true {
+				record = microsoftRecord
+			}
+		}
+		record.Position = position
+		record.Metadata = metadata
+		record.Comments = recordComments
+		provenance := Provenance{
+			Name:   SourceLine,
+			TTL:    SourceLine,
+			Class:  SourceLine,
+			Origin: currentOrigin,
+		}
 		if record.Name == "" {
 			if lastRecord.Name != "" {
 				record.Name = lastRecord.Name
+				provenance.Name = SourcePrevious
+			} else if currentOrigin != "" {
+				record.Name = currentOrigin
+				provenance.Name = SourceOrigin
 			} else {
+				provenance.Name = SourceAbsent
+			}
+		} else if record.Name == "@" {
+			// `@` denotes the current origin for every record, not only
+			// for the SOA record, per RFC 1035 §5.1.
+			provenance.Name = SourceAbsent
+			if currentOrigin != "" {
 				record.Name = currentOrigin
+				provenance.Name = SourceOrigin
 			}
 		}
 		if strings.HasSuffix(record.Name, ".") == false {
 			if currentOrigin != "" {
 				record.Name = record.Name + "." + currentOrigin
+				provenance.Qualified = true
 			}
 		}
-		if record.TTL == 0 {
-			if currentTtl > 0 {
+		if record.Class == "" {
+			record.Class, provenance.Class = inheritClass(lastRecord)
+		}
+		if provenance.Name == SourcePrevious || provenance.Class == SourcePrevious {
+			provenance.Previous = lastRecord.Position
+		}
+		if record.HasTTL == false {
+			if currentTtlSource != SourceAbsent {
 				record.TTL = currentTtl
+				provenance.TTL = currentTtlSource
 			} else {
 				record.TTL = zp.defaultTtl
+				provenance.TTL = SourceDefault
 			}
+			record.HasTTL = true
+		}
+		if zp.provenance == true {
+			record.Provenance = &provenance
+		}
+		if zp.digOutput == true && strings.EqualFold(record.Type, "OPT") {
+			continue
 		}
 
 		result.Records = append(result.Records, record)
 		lastRecord = record
 	}
+	keepComments()
 
 	return result, nil
 }
+
+// isSameRecord determines if two records have the same owner name, class,
+// type, TTL, and values.
+func isSameRecord(a ResourceRecord, b ResourceRecord) bool {
+	return strings.EqualFold(a.Name, b.Name) &&
+		strings.EqualFold(a.Class, b.Class) &&
+		strings.EqualFold(a.Type, b.Type) &&
+		a.TTL == b.TTL &&
+		slices.Equal(a.Values, b.Values)
+}
+
+// inheritClass determines the class for a record that omits one. The class of
+// the previous record is used when it is known, otherwise `IN` is assumed.
+func inheritClass(previous ResourceRecord) (string, ValueSource) {
+	if previous.Class != "" {
+		return previous.Class, SourcePrevious
+	}
+	return defaultClass, SourceDefault
+}
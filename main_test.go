@@ -37,6 +37,151 @@ func Test_WithDefaultTtl(t *testing.T) {
 	assert.Equal(t, expected.String(), found.String())
 }
 
+func Test_ExplicitZeroTtl(t *testing.T) {
+	zp, _ := NewZoneParser()
+	reader := strings.NewReader("$TTL 300\nfoo 0 IN A 1.2.3.4\nbar IN A 1.2.3.5\n")
+	found, err := zp.Parse(reader)
+	require.Nil(t, err)
+	assert.Equal(t, 0, found.Records[0].TTL)
+	assert.Equal(t, true, found.Records[0].HasTTL)
+	assert.Equal(t, 300, found.Records[1].TTL)
+	assert.Equal(t, "foo 0 IN A 1.2.3.4\nbar 300 IN A 1.2.3.5\n", found.String())
+
+	reader = strings.NewReader("$TTL 0\nfoo IN A 1.2.3.4\n")
+	found, err = zp.Parse(reader)
+	require.Nil(t, err)
+	assert.Equal(t, "foo 0 IN A 1.2.3.4\n", found.String())
+
+	rr := ResourceRecord{HasTTL: true}
+	assert.Equal(t, false, rr.IsEmpty())
+}
+
+func Test_ClassInheritance(t *testing.T) {
+	zp, _ := NewZoneParser()
+	reader := strings.NewReader("foo A 1.2.3.4\nbar CH A 1.2.3.5\nbaz A 1.2.3.6\n")
+	found, err := zp.Parse(reader)
+	require.Nil(t, err)
+	assert.Equal(t, "IN", found.Records[0].Class)
+	assert.Equal(t, "CH", found.Records[1].Class)
+	assert.Equal(t, "CH", found.Records[2].Class)
+
+	reader = strings.NewReader("@ 300 SOA ns. host. 1 2 3 4 5\ningress A 1.2.3.4\n")
+	found, err = zp.Parse(reader)
+	require.Nil(t, err)
+	assert.Equal(t, "IN", found.SOA.Class)
+	assert.Equal(t, "ingress", found.Records[0].Name)
+	assert.Equal(t, "IN", found.Records[0].Class)
+}
+
+func Test_AtOwner(t *testing.T) {
+	// `@` was only replaced for the SOA record, and other records were
+	// named `@.example.com.`.
+	z := mustParse(t, "$ORIGIN example.com.\n"+
+		"@ 300 SOA ns host 1 2 3 4 5\n"+
+		"@ 300 A 192.0.2.1\n"+
+		"$ORIGIN sub.example.com.\n"+
+		"@ 300 A 192.0.2.2\n")
+	assert.Equal(t, "example.com.", z.SOA.Name)
+	assert.Equal(t, "example.com.", z.Records[0].Name)
+	assert.Equal(t, "sub.example.com.", z.Records[1].Name)
+}
+
+func Test_BlankLines(t *testing.T) {
+	// Lines of white space, and indented comments, used to be read as
+	// records with a blank owner name, which made the parser panic.
+	z := mustParse(t, "www 300 IN A 192.0.2.1\r\n"+
+		"\r\n"+
+		"   \n"+
+		"\t; indented comment\n"+
+		"    300 IN A 192.0.2.2 ; same owner\n")
+	assert.Equal(t, "www 300 IN A 192.0.2.1\nwww 300 IN A 192.0.2.2\n", z.String())
+}
+
+func Test_Positions(t *testing.T) {
+	zp, _ := NewZoneParser(WithFileName("master17.txt"))
+	fd, err := testdataFS.Open("testdata/bind9/master17.txt")
+	require.Nil(t, err)
+	defer fd.Close()
+
+	found, err := zp.Parse(fd)
+	require.Nil(t, err)
+	assert.Equal(t, Position{File: "master17.txt", StartLine: 3, EndLine: 8}, found.SOA.Position)
+	assert.Equal(t, "master17.txt:3-8", found.SOA.Position.String())
+
+	require.Len(t, found.Records, 5)
+	assert.Equal(t, Position{File: "master17.txt", StartLine: 9, EndLine: 9}, found.Records[0].Position)
+	assert.Equal(t, "master17.txt:14", found.Records[4].Position.String())
+	assert.Nil(t, found.Records[4].Provenance)
+}
+
+func Test_WithProvenance(t *testing.T) {
+	zp, _ := NewZoneParser(WithProvenance(true))
+	fd, err := testdataFS.Open("testdata/bind9/master17.txt")
+	require.Nil(t, err)
+	defer fd.Close()
+
+	found, err := zp.Parse(fd)
+	require.Nil(t, err)
+	assert.Equal(t, &Provenance{
+		Name:   SourceOrigin,
+		TTL:    SourceTtlDirective,
+		Class:  SourceLine,
+		Origin: "test.",
+	}, found.SOA.Provenance)
+
+	// The owner of the last record is inherited from `b` even though the
+	// origin has changed.
+	assert.Equal(t, &Provenance{
+		Name:     SourcePrevious,
+		TTL:      SourceTtlDirective,
+		Class:    SourceLine,
+		Origin:   "sub.test.",
+		Previous: Position{StartLine: 12, EndLine: 12},
+	}, found.Records[4].Provenance)
+
+	assert.Equal(t, &Provenance{
+		Name:      SourceLine,
+		TTL:       SourceTtlDirective,
+		Class:     SourceLine,
+		Origin:    "test.",
+		Qualified: true,
+	}, found.Records[3].Provenance)
+
+	zp, _ = NewZoneParser(WithProvenance(true), WithPreferSoaMinTtl(true))
+	found, err = zp.Parse(strings.NewReader("@ in soa ns. host. 1 2 3 4 5\n@ in a 1.2.3.4\n"))
+	require.Nil(t, err)
+	assert.Equal(t, SourceSoaMinimum, found.SOA.Provenance.TTL)
+	assert.Equal(t, SourceSoaMinimum, found.Records[0].Provenance.TTL)
+	// Without an origin, `@` is kept, as no name could be derived for it.
+	assert.Equal(t, "@", found.SOA.Name)
+	assert.Equal(t, SourceAbsent, found.SOA.Provenance.Name)
+	assert.Equal(t, "@", found.Records[0].Name)
+	assert.Equal(t, SourceAbsent, found.Records[0].Provenance.Name)
+
+	zp, _ = NewZoneParser(WithProvenance(true), WithOrigin("example.com."))
+	found, err = zp.Parse(strings.NewReader("@ in soa ns. host. 1 2 3 4 5\n@ in a 1.2.3.4\n"))
+	require.Nil(t, err)
+	assert.Equal(t, "example.com.", found.Records[0].Name)
+	assert.Equal(t, SourceOrigin, found.SOA.Provenance.Name)
+	assert.Equal(t, SourceOrigin, found.Records[0].Provenance.Name)
+
+	zp, _ = NewZoneParser(WithProvenance(true))
+	found, err = zp.Parse(strings.NewReader("foo a 1.2.3.4\n"))
+	require.Nil(t, err)
+	assert.Equal(t, SourceDefault, found.Records[0].Provenance.TTL)
+	assert.Equal(t, SourceDefault, found.Records[0].Provenance.Class)
+
+	// A record that only inherits its class refers to the previous record.
+	found, err = zp.Parse(strings.NewReader("foo 300 in a 1.2.3.4\nbar 300 a 1.2.3.5\n"))
+	require.Nil(t, err)
+	assert.Equal(t, &Provenance{
+		Name:     SourceLine,
+		TTL:      SourceLine,
+		Class:    SourcePrevious,
+		Previous: Position{StartLine: 1, EndLine: 1},
+	}, found.Records[1].Provenance)
+}
+
 func Test_Fixtures(t *testing.T) {
 	zp, _ := NewZoneParser()
 	fixtures, err := readFixtures("testdata")
@@ -79,6 +224,97 @@ func Test_Bind9_Fixtures(t *testing.T) {
 	}
 }
 
+func Test_Dig_Fixtures(t *testing.T) {
+	zp, _ := NewZoneParser(WithDigOutput(true))
+	fixtures, err := readFixtures("testdata/dig")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	for name, fix := range fixtures {
+		t.Logf("testing fixture: %s", name)
+		found, err := zp.Parse(fix.input)
+		assert.Nil(t, err)
+		assert.Equal(t, fix.expected, found.String())
+	}
+}
+
+func Test_WithOrigin(t *testing.T) {
+	z := mustParse(t, `@ 300 IN SOA ns1 hostmaster 1 2 3 4 5
+www 300 IN A 192.0.2.1
+$ORIGIN example.net.
+www 300 IN A 192.0.2.2
+`, WithOrigin("example.com"))
+	assert.Equal(t, `example.com. 300 IN SOA ns1 hostmaster 1 2 3 4 5
+www.example.com. 300 IN A 192.0.2.1
+www.example.net. 300 IN A 192.0.2.2
+`, z.String())
+	assert.Equal(t, 2, z.Records[0].Position.StartLine)
+
+	_, err := NewZoneParser(WithOrigin(""))
+	assert.ErrorContains(t, err, "origin must not be empty")
+}
+
+func Test_WithComments(t *testing.T) {
+	input := `; generated by hand
+
+; the apex
+@ 300 IN SOA ns1 hostmaster ( 1 ; serial
+	2 3 4 5 ) ; timers
+www 300 IN A 192.0.2.1 ; web server
+; left behind
+$TTL 600
+mail IN A 192.0.2.2
+; trailing
+`
+	z := mustParse(t, input, WithComments(true))
+	assert.Equal(t, []string{"the apex", "serial", "timers"}, z.SOA.Comments)
+	assert.Equal(t, []string{"web server"}, z.Records[0].Comments)
+	assert.Nil(t, z.Records[1].Comments)
+	assert.Equal(t, []string{"generated by hand", "left behind", "trailing"}, z.Comments)
+
+	z = mustParse(t, input)
+	assert.Nil(t, z.SOA.Comments)
+	assert.Nil(t, z.Comments)
+}
+
+func Test_WithDigOutput(t *testing.T) {
+	z := mustParse(t, `;; QUESTION SECTION:
+example.com. IN A
+
+;; ANSWER SECTION:
+example.com. 300 IN A 192.0.2.1
+
+;; ADDITIONAL SECTION:
+. 0 CLASS1232 OPT
+`, WithDigOutput(true))
+	assert.Equal(t, "example.com. 300 IN A 192.0.2.1\n", z.String())
+
+	// The `CLASSnnn` form of classes is only recognized for OPT records of
+	// dig output.
+	z = mustParse(t, "host 300 CLASS1 A 192.0.2.1\n")
+	assert.Equal(t, "CLASS1", z.Records[0].Type)
+	assert.ErrorContains(t, z.Records[0].Validate(), "unknown type CLASS1")
+
+	// An IXFR lists differing SOA records, which are all kept.
+	z = mustParse(t, `example.com. 300 IN SOA ns1.example.com. hostmaster.example.com. 3 7200 3600 1209600 300
+example.com. 300 IN SOA ns1.example.com. hostmaster.example.com. 2 7200 3600 1209600 300
+example.com. 300 IN SOA ns1.example.com. hostmaster.example.com. 3 7200 3600 1209600 300
+`, WithDigOutput(true))
+	assert.Equal(t, "3", z.SOA.Values[2])
+	require.Len(t, z.Records, 1)
+	assert.Equal(t, "2", z.Records[0].Values[2])
+}
+
+// mustParse parses zone data that is expected to be valid.
+func mustParse(t *testing.T, data string, opts ...Option) *Zone {
+	t.Helper()
+	zp, err := NewZoneParser(opts...)
+	require.Nil(t, err)
+	z, err := zp.Parse(strings.NewReader(data))
+	require.Nil(t, err)
+	return z
+}
+
 type fixture struct {
 	input    fs.File
 	expected string
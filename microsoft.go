@@ -0,0 +1,121 @@
+package zone
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect selects the variant of the zone file format that [ZoneParser]
+// reads.
+type Dialect int
+
+const (
+	// DialectRFC1035 reads zone files as described in RFC 1035 §5.1.
+	DialectRFC1035 Dialect = iota
+
+	// DialectMicrosoft reads zone files written by Microsoft DNS Server,
+	// e.g. those exported from Active Directory integrated zones with
+	// `dnscmd /ZoneExport`. Records of dynamically registered names carry
+	// an `[AGE:nnnnnnn]` marker before their TTL, which is removed and kept
+	// in the [ResourceRecord.Metadata] under [MetadataMicrosoftAge].
+	//
+	// These files have no `$TTL` directive. The minimum field of the SOA
+	// record, which they label "default TTL", is the TTL of the records that
+	// omit one, unless the file sets `$TTL`.
+	//
+	// WINS and WINSR records, which only Microsoft DNS Server understands,
+	// are read with their types and values as written, e.g.
+	// `@ WINS L2 C900 192.0.2.1`, so that they can be reviewed during a
+	// migration. [ResourceRecord.Validate] reports them as unknown types.
+	DialectMicrosoft
+)
+
+// MetadataMicrosoftAge is the [ResourceRecord.Metadata] key of the aging
+// timestamp of a record read with [DialectMicrosoft]. It is the number of
+// hours since 1601-01-01 UTC, as written in the zone file. Use
+// [MicrosoftAgeTime] to convert it.
+const MetadataMicrosoftAge = "microsoft.age"
+
+// microsoftRecordTypes maps the record types that only Microsoft DNS Server
+// understands, in the spellings it accepts, to the names they are read as.
+var microsoftRecordTypes = map[string]string{
+	"WINS":   "WINS",
+	"WINSR":  "WINSR",
+	"WINS-R": "WINSR",
+}
+
+var microsoftAgeToken = regexp.MustCompile(`(?i)\[AGE:([0-9]+)\]`)
+
+// microsoftEpoch is the start of the aging timestamps of Microsoft DNS
+// Server.
+var microsoftEpoch = time.Date(1601, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// MicrosoftAgeTime converts the aging timestamp of a record, as found under
+// [MetadataMicrosoftAge], into the time the record was last refreshed.
+func MicrosoftAgeTime(value string) (time.Time, error) {
+	hours, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || hours < 0 {
+		return time.Time{}, fmt.Errorf("invalid aging timestamp: %s", value)
+	}
+	// The span exceeds the range of time.Duration.
+	return microsoftEpoch.AddDate(0, 0, int(hours/24)).Add(time.Duration(hours%24) * time.Hour), nil
+}
+
+// parseMicrosoftAge removes the `[AGE:nnnnnnn]` marker from a record line.
+// The metadata of the record is returned when the line has a marker.
+func parseMicrosoftAge(line []byte) ([]byte, map[string]string) {
+	matches := microsoftAgeToken.FindSubmatchIndex(line)
+	if matches == nil {
+		return line, nil
+	}
+	metadata := map[string]string{MetadataMicrosoftAge: string(line[matches[2]:matches[3]])}
+	result := make([]byte, 0, len(line))
+	result = append(result, line[:matches[0]]...)
+	result = append(result, ' ')
+	return append(result, line[matches[1]:]...), metadata
+}
+
+// parseMicrosoftRecordLine parses a line that holds a record of a type that
+// only Microsoft DNS Server understands. The second return value is false
+// when the line holds no such record.
+func parseMicrosoftRecordLine(line []byte) (ResourceRecord, bool) {
+	tokens := tokenizeLine(line)
+	for i, token := range tokens {
+		// The type follows at most an owner name, TTL, and class, and a
+		// known type before it means the line holds some other record, e.g.
+		// `www CNAME wins`.
+		if i > 3 {
+			break
+		}
+		recordType, ok := microsoftRecordTypes[strings.ToUpper(string(token))]
+		if ok == false {
+			if isRecordType(token) && (i > 0 || len(tokens) < 2 || (isClassToken.Match(tokens[1]) == false && isTtlToken.Match(tokens[1]) == false)) {
+				break
+			}
+			continue
+		}
+
+		result := ResourceRecord{Type: recordType}
+		prefix := tokens[:i]
+		if len(prefix) > 0 && isClassToken.Match(prefix[0]) == false && isTtlToken.Match(prefix[0]) == false {
+			result.Name = string(prefix[0])
+			prefix = prefix[1:]
+		}
+		for _, t := range prefix {
+			if isClassToken.Match(t) {
+				result.Class = string(t)
+			} else if isTtlToken.Match(t) {
+				result.TTL, _ = strconv.Atoi(string(t))
+				result.HasTTL = true
+			}
+		}
+		for _, t := range tokens[i+1:] {
+			result.Values = append(result.Values, string(t))
+		}
+		return result, true
+	}
+	return ResourceRecord{}, false
+}
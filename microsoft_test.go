@@ -0,0 +1,70 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Microsoft_Fixtures(t *testing.T) {
+	zp, _ := NewZoneParser(WithDialect(DialectMicrosoft))
+	fixtures, err := readFixtures("testdata/microsoft")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	for name, fix := range fixtures {
+		t.Logf("testing fixture: %s", name)
+		found, err := zp.Parse(fix.input)
+		assert.Nil(t, err)
+		assert.Equal(t, fix.expected, found.String())
+	}
+}
+
+func Test_WithDialect(t *testing.T) {
+	input := "printer [AGE:3710300] 1200 A 192.0.2.30\r\n" +
+		"\r\n" +
+		"   ; indented comment\r\n" +
+		"static A 192.0.2.31\r\n" +
+		"alias CNAME wins\r\n" +
+		"5.2.0.192.in-addr.arpa. 300 IN WINS-R LOCAL L2 C900 ( ad.example.com. )\r\n"
+	z := mustParse(t, input, WithDialect(DialectMicrosoft))
+	require.Len(t, z.Records, 4)
+	assert.Equal(t, map[string]string{MetadataMicrosoftAge: "3710300"}, z.Records[0].Metadata)
+	assert.Equal(t, "printer 1200 IN A 192.0.2.30\n", z.Records[0].String())
+	assert.Nil(t, z.Records[1].Metadata)
+	assert.Equal(t, "alias 86400 IN CNAME wins\n", z.Records[2].String())
+	assert.Equal(t, "5.2.0.192.in-addr.arpa. 300 IN WINSR LOCAL L2 C900 ad.example.com.\n", z.Records[3].String())
+	assert.ErrorContains(t, z.Records[3].Validate(), "unknown type WINSR")
+
+	// Without the dialect, the aging marker is not recognized.
+	z = mustParse(t, input)
+	assert.Nil(t, z.Records[0].Metadata)
+	assert.Equal(t, "WINS-R", z.Records[3].Type)
+
+	// The minimum of the SOA record is the default TTL, unless $TTL is set.
+	soa := "@ IN SOA dc1 hostmaster 1 900 600 86400 3600\nwww A 192.0.2.1\n"
+	z = mustParse(t, soa, WithDialect(DialectMicrosoft))
+	assert.Equal(t, 3600, z.SOA.TTL)
+	assert.Equal(t, 3600, z.Records[0].TTL)
+	z = mustParse(t, "$TTL 300\n"+soa, WithDialect(DialectMicrosoft))
+	assert.Equal(t, 300, z.SOA.TTL)
+	assert.Equal(t, 300, z.Records[0].TTL)
+	z = mustParse(t, soa)
+	assert.Equal(t, 86400, z.Records[0].TTL)
+
+	_, err := NewZoneParser(WithDialect(Dialect(42)))
+	assert.ErrorContains(t, err, "unknown dialect: 42")
+}
+
+func Test_MicrosoftAgeTime(t *testing.T) {
+	found, err := MicrosoftAgeTime("3710300")
+	require.Nil(t, err)
+	assert.Equal(t, time.Date(2024, time.April, 8, 20, 0, 0, 0, time.UTC), found)
+
+	for _, value := range []string{"", "-1", "soon", strings.Repeat("9", 12)} {
+		_, err = MicrosoftAgeTime(value)
+		assert.ErrorContains(t, err, "invalid aging timestamp", value)
+	}
+}
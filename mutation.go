@@ -0,0 +1,329 @@
+package zone
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MutationOption relaxes the invariants that the methods which change a
+// zone enforce, e.g. [Zone.Add].
+type MutationOption func(m *mutation)
+
+type mutation struct {
+	allowDuplicates    bool
+	allowTtlMismatch   bool
+	allowCNAMEConflict bool
+}
+
+// WithAllowDuplicates permits records that have the same [ResourceRecord.Key]
+// as a record of the zone when value is `true`.
+func WithAllowDuplicates(value bool) MutationOption {
+	return func(m *mutation) {
+		m.allowDuplicates = value
+	}
+}
+
+// WithAllowTtlMismatch permits records whose TTL differs from that of the
+// other records of their set when value is `true`.
+func WithAllowTtlMismatch(value bool) MutationOption {
+	return func(m *mutation) {
+		m.allowTtlMismatch = value
+	}
+}
+
+// WithAllowCNAMEConflict permits CNAME records to share their name with
+// other records, and with other CNAME records, when value is `true`.
+func WithAllowCNAMEConflict(value bool) MutationOption {
+	return func(m *mutation) {
+		m.allowCNAMEConflict = value
+	}
+}
+
+func newMutation(opts []MutationOption) *mutation {
+	m := &mutation{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Key identifies a record by its owner name, class, type, and values, but
+// not its TTL, which belongs to its record set. Names, including those in
+// the values, are compared case-insensitively. Two records with the same key
+// are duplicates.
+func (rr *ResourceRecord) Key() string {
+	values := make([]string, len(rr.Values))
+	copy(values, rr.Values)
+	if schema, ok := rdataSchema(rr.Type); ok == true && isGenericRdata(rr.Values) == false {
+		for i, v := range values {
+			if field, ok := rdataFieldAt(schema, i); ok == true && field.kind.isName() {
+				values[i] = strings.ToLower(v)
+			}
+		}
+	}
+	return rrsetKey(*rr) + " " + strings.Join(values, " ")
+}
+
+// Clone creates a deep copy of the zone, which can be changed without
+// affecting the original.
+func (z *Zone) Clone() *Zone {
+	result := &Zone{
+		Records: make([]ResourceRecord, 0, len(z.Records)),
+	}
+	if z.SOA.IsEmpty() == false {
+		result.SOA = cloneRecord(z.SOA)
+	}
+	for _, rr := range z.Records {
+		result.Records = append(result.Records, cloneRecord(rr))
+	}
+	if z.Comments != nil {
+		result.Comments = slices.Clone(z.Comments)
+	}
+	return result
+}
+
+func cloneRecord(rr ResourceRecord) ResourceRecord {
+	if rr.Values != nil {
+		rr.Values = slices.Clone(rr.Values)
+	}
+	if rr.Comments != nil {
+		rr.Comments = slices.Clone(rr.Comments)
+	}
+	if rr.Metadata != nil {
+		rr.Metadata = maps.Clone(rr.Metadata)
+	}
+	if rr.Provenance != nil {
+		provenance := *rr.Provenance
+		rr.Provenance = &provenance
+	}
+	return rr
+}
+
+// Equal determines if two zones hold the same records, with the same TTLs,
+// irrespective of their order. Records are compared by their
+// [ResourceRecord.Key]. Positions, comments, and other parser metadata are
+// ignored. A nil zone only equals another nil zone.
+func (z *Zone) Equal(other *Zone) bool {
+	if z == nil || other == nil {
+		return z == other
+	}
+	if z.SOA.IsEmpty() != other.SOA.IsEmpty() {
+		return false
+	}
+	if z.SOA.IsEmpty() == false && (z.SOA.Key() != other.SOA.Key() || z.SOA.TTL != other.SOA.TTL) {
+		return false
+	}
+	return slices.Equal(recordKeys(z.Records), recordKeys(other.Records))
+}
+
+// recordKeys lists the keys and TTLs of records in sorted order.
+func recordKeys(records []ResourceRecord) []string {
+	result := make([]string, 0, len(records))
+	for _, rr := range records {
+		result = append(result, rr.Key()+" "+strconv.Itoa(rr.TTL))
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Add adds a record to the zone. A SOA record becomes the SOA of the zone,
+// which must not have one. Other records are added after the records of
+// their set, or at the end.
+//
+// Unless relaxed with a [MutationOption], the record must not be a
+// duplicate, its TTL must equal that of the other records of its set, and a
+// CNAME record must be the only record at its name, except for the RRSIG
+// and NSEC records that RFC 4035 allows. Violations are reported as a
+// [RecordError] that wraps [ErrZoneInvariant], and the zone is not changed.
+func (z *Zone) Add(rr ResourceRecord, opts ...MutationOption) error {
+	return z.add(rr, newMutation(opts))
+}
+
+func (z *Zone) add(rr ResourceRecord, m *mutation) error {
+	if strings.EqualFold(rr.Type, "SOA") {
+		if z.SOA.IsEmpty() == false {
+			return &RecordError{Record: rr, Err: fmt.Errorf("zone already has a SOA record: %w", ErrZoneInvariant)}
+		}
+		z.SOA = rr
+		return nil
+	}
+
+	err := z.conflicts(rr, m)
+	if err != nil {
+		return &RecordError{Record: rr, Err: err}
+	}
+	key := rrsetKey(rr)
+	for i := len(z.Records) - 1; i >= 0; i-- {
+		if rrsetKey(z.Records[i]) == key {
+			z.Records = slices.Insert(z.Records, i+1, rr)
+			return nil
+		}
+	}
+	z.Records = append(z.Records, rr)
+	return nil
+}
+
+// conflicts checks that a record can be added to the zone without breaking
+// an invariant.
+func (z *Zone) conflicts(rr ResourceRecord, m *mutation) error {
+	key := rr.Key()
+	set := rrsetKey(rr)
+	isCNAME := strings.EqualFold(rr.Type, "CNAME")
+	for _, existing := range z.AllRecords() {
+		if strings.EqualFold(existing.Name, rr.Name) == false {
+			continue
+		}
+		if m.allowDuplicates == false && existing.Key() == key {
+			return fmt.Errorf("duplicate record: %w", ErrZoneInvariant)
+		}
+		if m.allowTtlMismatch == false && rrsetKey(existing) == set && existing.TTL != rr.TTL {
+			return fmt.Errorf("ttl %d differs from the ttl %d of its record set: %w", rr.TTL, existing.TTL, ErrZoneInvariant)
+		}
+		if m.allowCNAMEConflict == true || strings.EqualFold(existing.Class, rr.Class) == false {
+			continue
+		}
+		existingCNAME := strings.EqualFold(existing.Type, "CNAME")
+		if isCNAME && existingCNAME {
+			return fmt.Errorf("name already has a CNAME record: %w", ErrZoneInvariant)
+		}
+		if isCNAME && isCNAMESibling(existing.Type) == false {
+			return fmt.Errorf("CNAME record must be the only record at its name, but there is a %s record: %w", strings.ToUpper(existing.Type), ErrZoneInvariant)
+		}
+		if existingCNAME && isCNAMESibling(rr.Type) == false {
+			return fmt.Errorf("name has a CNAME record: %w", ErrZoneInvariant)
+		}
+	}
+	return nil
+}
+
+// isCNAMESibling determines if records of a type may share their name with a
+// CNAME record, per RFC 4035 §2.5.
+func isCNAMESibling(recordType string) bool {
+	return strings.EqualFold(recordType, "RRSIG") || strings.EqualFold(recordType, "NSEC")
+}
+
+// Remove removes the records, including the SOA record, that have the same
+// [ResourceRecord.Key] as rr. It reports whether a record was removed.
+func (z *Zone) Remove(rr ResourceRecord) bool {
+	key := rr.Key()
+	removed := false
+	if z.SOA.IsEmpty() == false && z.SOA.Key() == key {
+		z.SOA = ResourceRecord{}
+		removed = true
+	}
+	count := len(z.Records)
+	z.Records = slices.DeleteFunc(z.Records, func(existing ResourceRecord) bool {
+		return existing.Key() == key
+	})
+	return removed || len(z.Records) != count
+}
+
+// RemoveName removes every record, including the SOA record, whose owner
+// name equals name case-insensitively. It returns the number of records
+// removed.
+func (z *Zone) RemoveName(name string) int {
+	removed := 0
+	if z.SOA.IsEmpty() == false && strings.EqualFold(z.SOA.Name, name) {
+		z.SOA = ResourceRecord{}
+		removed += 1
+	}
+	count := len(z.Records)
+	z.Records = slices.DeleteFunc(z.Records, func(existing ResourceRecord) bool {
+		return strings.EqualFold(existing.Name, name)
+	})
+	return removed + count - len(z.Records)
+}
+
+// ReplaceRRSet replaces the records of the zone that have the name, class,
+// and type of the set with the records of the set, in the place of the first
+// record that is replaced, or at the end. Every record is given the name,
+// class, type, and TTL of the set. A set without records removes the set
+// from the zone.
+//
+// The invariants described for [Zone.Add] are enforced, and when one is
+// broken the zone is not changed.
+func (z *Zone) ReplaceRRSet(set RRSet, opts ...MutationOption) error {
+	m := newMutation(opts)
+	result := z.Clone()
+	target := rrsetKey(ResourceRecord{Name: set.Name, Class: set.Class, Type: set.Type})
+	if result.SOA.IsEmpty() == false && rrsetKey(result.SOA) == target {
+		result.SOA = ResourceRecord{}
+	}
+	index := -1
+	for i, existing := range result.Records {
+		if index < 0 && rrsetKey(existing) == target {
+			index = i
+		}
+	}
+	result.Records = slices.DeleteFunc(result.Records, func(existing ResourceRecord) bool {
+		return rrsetKey(existing) == target
+	})
+	if index < 0 {
+		index = len(result.Records)
+	}
+
+	added := &Zone{}
+	for _, rr := range set.Records {
+		rr = cloneRecord(rr)
+		rr.Name, rr.Class, rr.Type, rr.TTL = set.Name, set.Class, set.Type, set.TTL
+		rr.HasTTL = true
+		if strings.EqualFold(rr.Type, "SOA") {
+			err := result.add(rr, m)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := result.conflicts(rr, m)
+		if err == nil {
+			err = added.conflicts(rr, m)
+		}
+		if err != nil {
+			return &RecordError{Record: rr, Err: err}
+		}
+		added.Records = append(added.Records, rr)
+	}
+	z.SOA, z.Records = result.SOA, slices.Insert(result.Records, index, added.Records...)
+	return nil
+}
+
+// Rename changes the owner name of every record named from, compared
+// case-insensitively, to to. Names below from are not changed, and records
+// keep their place in the zone. The renamed records must not break the
+// invariants described for [Zone.Add] with the records already named to,
+// and when they do the zone is not changed.
+func (z *Zone) Rename(from string, to string, opts ...MutationOption) error {
+	m := newMutation(opts)
+	targets := &Zone{}
+	if strings.EqualFold(from, to) == false {
+		if z.SOA.IsEmpty() == false && strings.EqualFold(z.SOA.Name, to) {
+			targets.SOA = z.SOA
+		}
+		for _, existing := range z.Records {
+			if strings.EqualFold(existing.Name, to) {
+				targets.Records = append(targets.Records, existing)
+			}
+		}
+	}
+
+	result := z.Clone()
+	if result.SOA.IsEmpty() == false && strings.EqualFold(result.SOA.Name, from) {
+		result.SOA.Name = to
+	}
+	for i, rr := range result.Records {
+		if strings.EqualFold(rr.Name, from) == false {
+			continue
+		}
+		rr.Name = to
+		err := targets.conflicts(rr, m)
+		if err != nil {
+			return &RecordError{Record: rr, Err: err}
+		}
+		result.Records[i] = rr
+	}
+	z.SOA, z.Records = result.SOA, result.Records
+	return nil
+}
@@ -0,0 +1,156 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const mutationZone = `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 3600 IN NS ns1
+www 300 IN A 192.0.2.1
+www 300 IN A 192.0.2.2
+ftp 300 IN CNAME www
+mail 300 IN A 192.0.2.3
+`
+
+func Test_ResourceRecord_Key(t *testing.T) {
+	a := ResourceRecord{Name: "WWW.example.com.", Class: "in", Type: "cname", TTL: 60, Values: []string{"Web.example.com."}}
+	b := ResourceRecord{Name: "www.example.com.", Class: "IN", Type: "CNAME", TTL: 300, Values: []string{"web.EXAMPLE.com."}}
+	assert.Equal(t, "www.example.com. IN CNAME web.example.com.", a.Key())
+	assert.Equal(t, a.Key(), b.Key())
+
+	// Text is compared as given.
+	c := ResourceRecord{Name: "a", Class: "IN", Type: "TXT", Values: []string{`"Hello"`}}
+	d := ResourceRecord{Name: "a", Class: "IN", Type: "TXT", Values: []string{`"hello"`}}
+	assert.NotEqual(t, c.Key(), d.Key())
+}
+
+func Test_Zone_Add(t *testing.T) {
+	z := mustParse(t, mutationZone)
+	err := z.Add(ResourceRecord{Name: "www.example.com.", Class: "IN", Type: "A", TTL: 300, Values: []string{"192.0.2.9"}})
+	require.Nil(t, err)
+	assert.Equal(t, "192.0.2.9", z.Records[3].Values[0])
+	err = z.Add(ResourceRecord{Name: "new.example.com.", Class: "IN", Type: "AAAA", TTL: 300, Values: []string{"2001:db8::1"}})
+	require.Nil(t, err)
+	assert.Equal(t, "new.example.com.", z.Records[len(z.Records)-1].Name)
+
+	tests := []struct {
+		rr       ResourceRecord
+		expected string
+	}{
+		{rr: ResourceRecord{Name: "WWW.example.com.", Class: "IN", Type: "A", TTL: 300, Values: []string{"192.0.2.1"}}, expected: "duplicate record"},
+		{rr: ResourceRecord{Name: "www.example.com.", Class: "IN", Type: "A", TTL: 60, Values: []string{"192.0.2.5"}}, expected: "ttl 60 differs from the ttl 300 of its record set"},
+		{rr: ResourceRecord{Name: "ftp.example.com.", Class: "IN", Type: "TXT", TTL: 300, Values: []string{`"x"`}}, expected: "name has a CNAME record"},
+		{rr: ResourceRecord{Name: "ftp.example.com.", Class: "IN", Type: "CNAME", TTL: 300, Values: []string{"mail"}}, expected: "name already has a CNAME record"},
+		{rr: ResourceRecord{Name: "mail.example.com.", Class: "IN", Type: "CNAME", TTL: 300, Values: []string{"www"}}, expected: "there is a A record"},
+		{rr: ResourceRecord{Name: "example.com.", Class: "IN", Type: "CNAME", TTL: 300, Values: []string{"www"}}, expected: "there is a SOA record"},
+		{rr: ResourceRecord{Name: "example.com.", Class: "IN", Type: "SOA", TTL: 300, Values: []string{"ns2", "hostmaster", "2", "1", "1", "1", "1"}}, expected: "zone already has a SOA record"},
+	}
+	count := len(z.Records)
+	for _, test := range tests {
+		err := z.Add(test.rr)
+		assert.ErrorIs(t, err, ErrZoneInvariant, test.expected)
+		assert.ErrorContains(t, err, test.expected)
+	}
+	assert.Len(t, z.Records, count)
+
+	require.Nil(t, z.Add(tests[0].rr, WithAllowDuplicates(true)))
+	require.Nil(t, z.Add(tests[1].rr, WithAllowTtlMismatch(true)))
+	require.Nil(t, z.Add(tests[2].rr, WithAllowCNAMEConflict(true)))
+	require.Nil(t, z.Add(ResourceRecord{Name: "ftp.example.com.", Class: "IN", Type: "RRSIG", TTL: 300, Values: []string{"CNAME", "8", "3", "300", "20240101000000", "20230101000000", "1", "example.com.", "AAAA"}}))
+	assert.Len(t, z.Records, count+4)
+}
+
+func Test_Zone_Remove(t *testing.T) {
+	z := mustParse(t, mutationZone)
+	assert.True(t, z.Remove(ResourceRecord{Name: "WWW.example.com.", Class: "IN", Type: "A", Values: []string{"192.0.2.2"}}))
+	assert.False(t, z.Remove(ResourceRecord{Name: "www.example.com.", Class: "IN", Type: "A", Values: []string{"192.0.2.2"}}))
+	assert.Len(t, z.Records, 4)
+
+	assert.Equal(t, 0, z.RemoveName("nothing.example.com."))
+	assert.Equal(t, 2, z.RemoveName("EXAMPLE.com."))
+	assert.True(t, z.SOA.IsEmpty())
+	assert.Len(t, z.Records, 3)
+}
+
+func Test_Zone_ReplaceRRSet(t *testing.T) {
+	z := mustParse(t, mutationZone)
+	err := z.ReplaceRRSet(RRSet{Name: "www.example.com.", Class: "IN", Type: "A", TTL: 60, Records: []ResourceRecord{
+		{Values: []string{"198.51.100.1"}},
+		{TTL: 5, Values: []string{"198.51.100.2"}},
+	}})
+	require.Nil(t, err)
+	www, err := z.Select("name=www.example.com.")
+	require.Nil(t, err)
+	assert.Equal(t, "www.example.com. 60 IN A 198.51.100.1\nwww.example.com. 60 IN A 198.51.100.2\n", www.String())
+
+	err = z.ReplaceRRSet(RRSet{Name: "ftp.example.com.", Class: "IN", Type: "CNAME", TTL: 60, Records: []ResourceRecord{
+		{Values: []string{"a"}},
+		{Values: []string{"b"}},
+	}})
+	assert.ErrorContains(t, err, "name already has a CNAME record")
+	assert.Equal(t, "www", z.Records[3].Values[0])
+
+	require.Nil(t, z.ReplaceRRSet(RRSet{Name: "ftp.example.com.", Class: "IN", Type: "CNAME"}))
+	assert.Len(t, z.Records, 4)
+
+	err = z.ReplaceRRSet(RRSet{Name: "example.com.", Class: "IN", Type: "SOA", TTL: 60, Records: []ResourceRecord{
+		{Values: []string{"ns2", "hostmaster", "2", "7200", "3600", "1209600", "300"}},
+	}})
+	require.Nil(t, err)
+	assert.Equal(t, "example.com. 60 IN SOA ns2 hostmaster 2 7200 3600 1209600 300\n", z.SOA.String())
+}
+
+func Test_Zone_Rename(t *testing.T) {
+	z := mustParse(t, mutationZone)
+	require.Nil(t, z.Rename("WWW.example.com.", "web.example.com."))
+	assert.Equal(t, "web.example.com.", z.Records[1].Name)
+	assert.Equal(t, "web.example.com.", z.Records[2].Name)
+
+	err := z.Rename("ftp.example.com.", "mail.example.com.")
+	assert.ErrorIs(t, err, ErrZoneInvariant)
+	assert.Equal(t, "ftp.example.com.", z.Records[3].Name)
+
+	require.Nil(t, z.Rename("ftp.example.com.", "mail.example.com.", WithAllowCNAMEConflict(true)))
+	assert.Equal(t, "mail.example.com.", z.Records[3].Name)
+
+	// The SOA record is at the apex too.
+	z = mustParse(t, "$ORIGIN example.com.\n@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300\nalias 300 IN CNAME www\n")
+	err = z.Rename("alias.example.com.", "example.com.")
+	assert.ErrorIs(t, err, ErrZoneInvariant)
+	assert.ErrorContains(t, err, "there is a SOA record")
+	assert.Equal(t, "alias.example.com.", z.Records[0].Name)
+}
+
+func Test_Zone_Clone_Equal(t *testing.T) {
+	z := mustParse(t, mutationZone, WithComments(true), WithProvenance(true))
+	clone := z.Clone()
+	assert.Equal(t, z, clone)
+	assert.True(t, z.Equal(clone))
+
+	clone.Records[0].Values[0] = "ns2"
+	clone.Records[0].Provenance.Origin = "example.net."
+	assert.Equal(t, "ns1", z.Records[0].Values[0])
+	assert.Equal(t, "example.com.", z.Records[0].Provenance.Origin)
+	assert.False(t, z.Equal(clone))
+
+	// Order and parser metadata do not matter.
+	other := mustParse(t, `$ORIGIN example.com.
+mail 300 IN A 192.0.2.3
+www 300 IN A 192.0.2.2
+www 300 IN A 192.0.2.1
+FTP 300 IN CNAME www
+@ 3600 IN NS ns1
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+`)
+	assert.True(t, z.Equal(other))
+	other.Records[0].TTL = 60
+	assert.False(t, z.Equal(other))
+
+	var none *Zone
+	assert.False(t, z.Equal(nil))
+	assert.False(t, none.Equal(z))
+	assert.True(t, none.Equal(nil))
+}
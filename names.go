@@ -0,0 +1,70 @@
+package zone
+
+import "strings"
+
+// isAbsoluteName determines if a domain name is fully qualified, i.e. it
+// ends with a dot that has not been escaped.
+func isAbsoluteName(name string) bool {
+	return strings.HasSuffix(name, ".") && strings.HasSuffix(name, `\.`) == false
+}
+
+// fqdn ensures a domain name ends with a dot. It is used to normalize apex
+// names provided by callers.
+func fqdn(name string) string {
+	if isAbsoluteName(name) {
+		return name
+	}
+	return name + "."
+}
+
+// qualifyName converts a relative domain name to an absolute name under
+// the origin. Both `@` and an empty name denote the origin itself. Names are
+// returned unchanged when they are already absolute or no origin is known.
+func qualifyName(name string, origin string) string {
+	if name == "" || name == "@" {
+		if origin == "" {
+			return name
+		}
+		return origin
+	}
+	if isAbsoluteName(name) || origin == "" {
+		return name
+	}
+	if origin == "." {
+		return name + "."
+	}
+	return name + "." + origin
+}
+
+// isInZone determines if an absolute domain name is at, or below, the apex.
+// Domain names are compared case-insensitively.
+func isInZone(name string, apex string) bool {
+	name = strings.ToLower(name)
+	apex = strings.ToLower(apex)
+	if apex == "." {
+		return true
+	}
+	return name == apex || strings.HasSuffix(name, "."+apex)
+}
+
+// relativeName converts a domain name to a name relative to the apex, where
+// the apex itself is `@`. Relative names are returned unchanged. The second
+// return value is false when an absolute name lies outside the apex.
+func relativeName(name string, apex string) (string, bool) {
+	if name == "" || name == "@" {
+		return "@", true
+	}
+	if isAbsoluteName(name) == false {
+		return name, true
+	}
+	if isInZone(name, apex) == false {
+		return name, false
+	}
+	if len(name) == len(apex) {
+		return "@", true
+	}
+	if apex == "." {
+		return strings.TrimSuffix(name, "."), true
+	}
+	return name[:len(name)-len(apex)-1], true
+}
@@ -0,0 +1,46 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_qualifyName(t *testing.T) {
+	tests := [][]string{
+		{"www", "example.com.", "www.example.com."},
+		{"@", "example.com.", "example.com."},
+		{"", "example.com.", "example.com."},
+		{"www.example.net.", "example.com.", "www.example.net."},
+		{"www", "", "www"},
+		{"www", ".", "www."},
+		{`foo\.`, "example.com.", `foo\..example.com.`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test[2], qualifyName(test[0], test[1]))
+	}
+}
+
+func Test_relativeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		apex     string
+		expected string
+		ok       bool
+	}{
+		{"www.example.com.", "example.com.", "www", true},
+		{"WWW.Example.COM.", "example.com.", "WWW", true},
+		{"example.com.", "example.com.", "@", true},
+		{"", "example.com.", "@", true},
+		{"www", "example.com.", "www", true},
+		{"www.example.net.", "example.com.", "www.example.net.", false},
+		{"notexample.com.", "example.com.", "notexample.com.", false},
+		{"www.example.com.", ".", "www.example.com", true},
+	}
+
+	for _, test := range tests {
+		found, ok := relativeName(test.name, test.apex)
+		assert.Equal(t, test.expected, found)
+		assert.Equal(t, test.ok, ok)
+	}
+}
@@ -0,0 +1,251 @@
+package zone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// nsupdateMaxSize is the largest DNS message, in bytes, which is the default
+// limit on the size of each update.
+const nsupdateMaxSize = 65535
+
+// nsupdateHeaderSize is the size of the header and zone section of an
+// update message, excluding the zone name.
+const nsupdateHeaderSize = 12 + 4
+
+type NsupdateOption func(generator *nsupdateGenerator) error
+
+type nsupdateGenerator struct {
+	apex          string
+	maxSize       int
+	prerequisites bool
+	server        string
+}
+
+// WithNsupdateServer adds a `server` line that directs the updates to the
+// given server, with an optional port, e.g. `192.0.2.53` or
+// `ns1.example.com:5353`.
+func WithNsupdateServer(server string) NsupdateOption {
+	return func(generator *nsupdateGenerator) error {
+		host, port, err := net.SplitHostPort(server)
+		if err != nil {
+			host, port = strings.Trim(server, "[]"), ""
+		}
+		if host == "" || strings.ContainsAny(host, " \t") {
+			return fmt.Errorf("invalid nsupdate server: %q", server)
+		}
+		generator.server = host
+		if port != "" {
+			generator.server += " " + port
+		}
+		return nil
+	}
+}
+
+// WithNsupdatePrerequisites adds prerequisites to every update when value
+// is `true`, so that an update is only applied when the zone is in the state
+// it is expected to be in. Record sets that are updated or deleted must
+// exist with exactly their old records (`prereq yxrrset` with data), and
+// added record sets must not exist (`prereq nxrrset`).
+func WithNsupdatePrerequisites(value bool) NsupdateOption {
+	return func(generator *nsupdateGenerator) error {
+		generator.prerequisites = value
+		return nil
+	}
+}
+
+// WithNsupdateMaxSize limits the estimated size, in bytes, of the DNS
+// message that each `send` produces. The changes to a record set are never
+// divided between messages. The default is `65_535`, the largest DNS
+// message.
+func WithNsupdateMaxSize(value int) NsupdateOption {
+	return func(generator *nsupdateGenerator) error {
+		if value < 512 || value > nsupdateMaxSize {
+			return fmt.Errorf("nsupdate messages must be between 512 and %d bytes: %d", nsupdateMaxSize, value)
+		}
+		generator.maxSize = value
+		return nil
+	}
+}
+
+// WriteNsupdate writes an [nsupdate] script that applies record set
+// changes, as produced by [Diff], to the zone at apex. For example,
+// `Diff(old, new)` yields the script that transforms the old zone into the
+// new zone.
+//
+// Deleted sets are removed with `update delete`. Updated sets are removed
+// and then added again with their new records. The SOA record is never
+// deleted, and a changed SOA record is only added, which replaces it. The
+// NS set at the apex is changed by adding its new records, and then
+// deleting each old record that is not among them, as servers ignore the
+// deletion of the whole set.
+// Changes are grouped into messages, each ended by `send`, that stay under
+// the size set with [WithNsupdateMaxSize].
+//
+// Owner names and domain names within record values are qualified with
+// apex. Records that are outside apex are reported as [RecordError] values
+// in the returned error, and nothing is written.
+//
+// [nsupdate]: https://bind9.readthedocs.io/en/latest/manpages.html#nsupdate-dynamic-dns-update-utility
+func WriteNsupdate(w io.Writer, changes []RRSetChange, apex string, opts ...NsupdateOption) error {
+	generator := &nsupdateGenerator{
+		apex:    fqdn(apex),
+		maxSize: nsupdateMaxSize,
+	}
+	for _, opt := range opts {
+		err := opt(generator)
+		if err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	buf := bytes.Buffer{}
+	if generator.server != "" {
+		buf.WriteString("server " + generator.server + "\n")
+	}
+	buf.WriteString("zone " + generator.apex + "\n")
+
+	baseSize := nsupdateHeaderSize + nsupdateNameSize(generator.apex)
+	messageSize, messageChanges := baseSize, 0
+	for _, change := range changes {
+		lines, size, err := generator.lines(change)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		if baseSize+size > generator.maxSize {
+			set := change.New
+			if change.Kind == ChangeDelete {
+				set = change.Old
+			}
+			errs = append(errs, fmt.Errorf("%s %s: change of %d bytes exceeds the message size", set.Name, set.Type, size))
+			continue
+		}
+
+		if messageChanges > 0 && messageSize+size > generator.maxSize {
+			buf.WriteString("send\n")
+			messageSize, messageChanges = baseSize, 0
+		}
+		buf.WriteString(strings.Join(lines, "\n") + "\n")
+		messageSize += size
+		messageChanges += 1
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if messageChanges > 0 {
+		buf.WriteString("send\n")
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// lines renders the prerequisite and update lines of a change, along with
+// the estimated number of bytes they add to an update message.
+func (generator *nsupdateGenerator) lines(change RRSetChange) ([]string, int, error) {
+	oldRecords, err := generator.qualify(change.Old)
+	if err != nil {
+		return nil, 0, err
+	}
+	newRecords, err := generator.qualify(change.New)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	set := change.New
+	if change.Kind == ChangeDelete {
+		set = change.Old
+	}
+	isSoa := strings.EqualFold(set.Type, "SOA")
+	if isSoa && change.Kind == ChangeDelete {
+		return nil, 0, nil
+	}
+	name := qualifyName(set.Name, generator.apex)
+	key := name + " " + strings.ToUpper(set.Class) + " " + strings.ToUpper(set.Type)
+	emptySize := nsupdateNameSize(name) + 10
+
+	result := make([]string, 0)
+	size := 0
+	if generator.prerequisites == true {
+		if change.Kind == ChangeAdd {
+			result = append(result, "prereq nxrrset "+key)
+			size += emptySize
+		} else {
+			for _, rr := range oldRecords {
+				result = append(result, "prereq yxrrset "+key+" "+strings.Join(rr.Values, " "))
+				size += emptySize + nsupdateRdataSize(rr)
+			}
+		}
+	}
+	// Servers ignore the deletion of the whole NS set at the apex, see RFC
+	// 2136 §3.4.2.3, so the records that are no longer wanted are deleted
+	// one by one after the new records have been added.
+	isApexNS := strings.EqualFold(set.Type, "NS") && strings.EqualFold(name, generator.apex)
+	if change.Kind != ChangeAdd && isSoa == false && isApexNS == false {
+		result = append(result, "update delete "+key)
+		size += emptySize
+	}
+	for _, rr := range newRecords {
+		result = append(result, "update add "+name+" "+strconv.Itoa(set.TTL)+" "+strings.ToUpper(rr.Class)+" "+strings.ToUpper(rr.Type)+" "+strings.Join(rr.Values, " "))
+		size += emptySize + nsupdateRdataSize(rr)
+	}
+	if isApexNS == true {
+		keep := make(map[string]bool)
+		for _, rr := range newRecords {
+			keep[rr.Key()] = true
+		}
+		for _, rr := range oldRecords {
+			if change.Kind == ChangeDelete || keep[rr.Key()] == false {
+				result = append(result, "update delete "+key+" "+strings.Join(rr.Values, " "))
+				size += emptySize + nsupdateRdataSize(rr)
+			}
+		}
+	}
+	return result, size, nil
+}
+
+// qualify qualifies the names of the records of a set, and verifies that
+// they are within the zone.
+func (generator *nsupdateGenerator) qualify(set RRSet) ([]ResourceRecord, error) {
+	result := make([]ResourceRecord, 0, len(set.Records))
+	var errs []error
+	for _, rr := range set.Records {
+		rr = qualifyRdata(rr, generator.apex)
+		rr.Name = qualifyName(rr.Name, generator.apex)
+		if isInZone(rr.Name, generator.apex) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrOutOfZone})
+			continue
+		}
+		result = append(result, rr)
+	}
+	return result, errors.Join(errs...)
+}
+
+// nsupdateNameSize estimates the size of an uncompressed domain name.
+func nsupdateNameSize(name string) int {
+	wire, err := packName(nil, name)
+	if err != nil {
+		return len(name) + 1
+	}
+	return len(wire)
+}
+
+// nsupdateRdataSize estimates the size of the RDATA of a record. The
+// presentation format is used when the wire format cannot be determined.
+func nsupdateRdataSize(rr ResourceRecord) int {
+	rdata, err := packRdata(rr)
+	if err != nil {
+		return len(strings.Join(rr.Values, " "))
+	}
+	return len(rdata)
+}
@@ -0,0 +1,131 @@
+package zone
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_WriteNsupdate(t *testing.T) {
+	old := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+www 300 IN A 192.0.2.1
+www 300 IN A 192.0.2.2
+ftp 300 IN A 192.0.2.3
+`)
+	new := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 2 7200 3600 1209600 300
+www 300 IN A 192.0.2.2
+api 300 IN CNAME www
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteNsupdate(&buf, Diff(old, new), "example.com", WithNsupdateServer("192.0.2.53"))
+	require.Nil(t, err)
+	assert.Equal(t, `server 192.0.2.53
+zone example.com.
+update delete ftp.example.com. IN A
+update add example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 2 7200 3600 1209600 300
+update delete www.example.com. IN A
+update add www.example.com. 300 IN A 192.0.2.2
+update add api.example.com. 300 IN CNAME www.example.com.
+send
+`, buf.String())
+
+	buf.Reset()
+	err = WriteNsupdate(&buf, Diff(old, new), "example.com.",
+		WithNsupdateServer("[2001:db8::53]:5353"),
+		WithNsupdatePrerequisites(true),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, `server 2001:db8::53 5353
+zone example.com.
+prereq yxrrset ftp.example.com. IN A 192.0.2.3
+update delete ftp.example.com. IN A
+prereq yxrrset example.com. IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+update add example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 2 7200 3600 1209600 300
+prereq yxrrset www.example.com. IN A 192.0.2.1
+prereq yxrrset www.example.com. IN A 192.0.2.2
+update delete www.example.com. IN A
+update add www.example.com. 300 IN A 192.0.2.2
+prereq nxrrset api.example.com. IN CNAME
+update add api.example.com. 300 IN CNAME www.example.com.
+send
+`, buf.String())
+
+	// Deleting the zone never deletes the SOA record.
+	buf.Reset()
+	err = WriteNsupdate(&buf, Diff(old, nil), "example.com.")
+	require.Nil(t, err)
+	assert.NotContains(t, buf.String(), "SOA")
+}
+
+func Test_WriteNsupdate_ApexNS(t *testing.T) {
+	old := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN NS ns1
+@ 3600 IN NS ns2
+sub 3600 IN NS ns1
+`)
+	new := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN NS NS2
+@ 3600 IN NS ns3
+sub 3600 IN NS ns3
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteNsupdate(&buf, Diff(old, new), "example.com.")
+	require.Nil(t, err)
+	assert.Equal(t, `zone example.com.
+update add example.com. 3600 IN NS NS2.example.com.
+update add example.com. 3600 IN NS ns3.example.com.
+update delete example.com. IN NS ns1.example.com.
+update delete sub.example.com. IN NS
+update add sub.example.com. 3600 IN NS ns3.example.com.
+send
+`, buf.String())
+}
+
+func Test_WriteNsupdate_Batches(t *testing.T) {
+	data := strings.Builder{}
+	data.WriteString("$ORIGIN example.com.\n")
+	for i := 0; i < 40; i++ {
+		data.WriteString(fmt.Sprintf("host%d 300 IN A 192.0.2.%d\n", i, i))
+	}
+	z := mustParse(t, data.String())
+
+	buf := bytes.Buffer{}
+	err := WriteNsupdate(&buf, Diff(nil, z), "example.com.", WithNsupdateMaxSize(512))
+	require.Nil(t, err)
+
+	// Each message has a 29 byte header and zone section, and each record
+	// takes 33 or 34 bytes.
+	script := buf.String()
+	assert.Equal(t, 3, strings.Count(script, "send\n"))
+	messages := strings.Split(strings.TrimPrefix(script, "zone example.com.\n"), "send\n")
+	assert.Equal(t, 14, strings.Count(messages[0], "update add"))
+	assert.Equal(t, 40, strings.Count(script, "update add"))
+
+	z = mustParse(t, `$ORIGIN example.com.
+@ 300 IN TXT "`+strings.Repeat("x", 250)+`" "`+strings.Repeat("y", 250)+`"
+`)
+	err = WriteNsupdate(&buf, Diff(nil, z), "example.com.", WithNsupdateMaxSize(512))
+	assert.ErrorContains(t, err, "exceeds the message size")
+
+	err = WriteNsupdate(&buf, nil, "example.com.", WithNsupdateMaxSize(100))
+	assert.ErrorContains(t, err, "nsupdate messages must be between 512 and 65535 bytes")
+}
+
+func Test_WriteNsupdate_Errors(t *testing.T) {
+	z := mustParse(t, "www.example.net. 300 IN A 192.0.2.1\n")
+
+	buf := bytes.Buffer{}
+	err := WriteNsupdate(&buf, Diff(nil, z), "example.com.")
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.Empty(t, buf.String())
+
+	err = WriteNsupdate(&buf, nil, "example.com.", WithNsupdateServer(""))
+	assert.ErrorContains(t, err, "invalid nsupdate server")
+}
@@ -0,0 +1,299 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// octoDNSDefaultTtl is the TTL octoDNS assumes for records that omit one.
+const octoDNSDefaultTtl = 3600
+
+// octoDNSTypes lists the octoDNS record types that can be converted.
+var octoDNSTypes = []string{
+	"A", "AAAA", "CAA", "CNAME", "DNAME", "DS", "MX", "NAPTR", "NS", "PTR",
+	"SPF", "SRV", "SSHFP", "TLSA", "TXT",
+}
+
+// octoDNSFieldAliases maps the current octoDNS value keys of a type to the
+// legacy keys that are read when the current key is absent.
+var octoDNSFieldAliases = map[string]map[string]string{
+	"MX": {"preference": "priority", "exchange": "value"},
+}
+
+// octoDNSRecord is a single record entry of an octoDNS zone file. Records
+// with one value use Value, otherwise Values is used. Values are either
+// strings or, for record types with multiple fields, maps of field names.
+type octoDNSRecord struct {
+	Type   string `yaml:"type"`
+	TTL    *int   `yaml:"ttl,omitempty"`
+	Value  any    `yaml:"value,omitempty"`
+	Values []any  `yaml:"values,omitempty"`
+}
+
+// WriteOctoDNS writes the records of a zone as an [octoDNS] YAML zone file
+// for the zone at apex. Names are written relative to apex, and domain names
+// within record values are qualified with apex as octoDNS requires.
+//
+// The SOA record is omitted because octoDNS providers manage it. Records
+// that octoDNS cannot represent, or that are outside apex, are reported as
+// [RecordError] values in the returned error, and nothing is written.
+//
+// [octoDNS]: https://github.com/octodns/octodns
+func WriteOctoDNS(w io.Writer, z *Zone, apex string) error {
+	apex = fqdn(apex)
+
+	var errs []error
+	records := make([]ResourceRecord, 0, len(z.Records))
+	for _, rr := range z.Records {
+		if strings.EqualFold(rr.Type, "SOA") {
+			continue
+		}
+		rr.Name = qualifyName(rr.Name, apex)
+		if isInZone(rr.Name, apex) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrOutOfZone})
+			continue
+		}
+		records = append(records, rr)
+	}
+
+	document := make(map[string][]octoDNSRecord)
+	for _, set := range groupRRSets(records) {
+		ttl := set.TTL
+		entry := octoDNSRecord{
+			Type: strings.ToUpper(set.Type),
+			TTL:  &ttl,
+		}
+		for _, rr := range set.Records {
+			value, err := octoDNSValue(qualifyRdata(rr, apex))
+			if err != nil {
+				errs = append(errs, &RecordError{Record: rr, Err: err})
+				continue
+			}
+			entry.Values = append(entry.Values, value)
+		}
+		if len(entry.Values) == 1 {
+			entry.Value, entry.Values = entry.Values[0], nil
+		}
+
+		name, _ := relativeName(set.Name, apex)
+		if name == "@" {
+			name = ""
+		}
+		document[name] = append(document[name], entry)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	output := make(map[string]any, len(document))
+	for name, entries := range document {
+		slices.SortFunc(entries, func(a, b octoDNSRecord) int {
+			return strings.Compare(a.Type, b.Type)
+		})
+		if len(entries) == 1 {
+			output[name] = entries[0]
+		} else {
+			output[name] = entries
+		}
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	err := encoder.Encode(output)
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// ParseOctoDNS reads an [octoDNS] YAML zone file for the zone at apex into a
+// [Zone]. Names are qualified with apex, and records without a TTL receive
+// the octoDNS default of 3600.
+//
+// [octoDNS]: https://github.com/octodns/octodns
+func ParseOctoDNS(reader io.Reader, apex string) (*Zone, error) {
+	var document yaml.Node
+	err := yaml.NewDecoder(reader).Decode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("parse octoDNS zone: %w", err)
+	}
+
+	apex = fqdn(apex)
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	if len(document.Content) == 0 {
+		return result, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse octoDNS zone: line %d: expected a map of names", root.Line)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name, node := root.Content[i].Value, root.Content[i+1]
+
+		var entries []octoDNSRecord
+		if node.Kind == yaml.SequenceNode {
+			err = node.Decode(&entries)
+		} else {
+			entries = make([]octoDNSRecord, 1)
+			err = node.Decode(&entries[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse octoDNS zone: %s: %w", name, err)
+		}
+
+		for _, entry := range entries {
+			records, err := octoDNSRecords(qualifyName(name, apex), entry)
+			if err != nil {
+				return nil, fmt.Errorf("parse octoDNS zone: line %d: %w", node.Line, err)
+			}
+			result.Records = append(result.Records, records...)
+		}
+	}
+	return result, nil
+}
+
+// octoDNSRecords converts an octoDNS record entry into resource records.
+func octoDNSRecords(name string, entry octoDNSRecord) ([]ResourceRecord, error) {
+	recordType := strings.ToUpper(entry.Type)
+	if slices.Contains(octoDNSTypes, recordType) == false {
+		return nil, fmt.Errorf("%s %s: %w", name, entry.Type, ErrUnsupportedRecord)
+	}
+
+	ttl := octoDNSDefaultTtl
+	if entry.TTL != nil {
+		ttl = *entry.TTL
+	}
+	values := entry.Values
+	if len(values) == 0 && entry.Value != nil {
+		values = []any{entry.Value}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s %s: record has no values: %w", name, recordType, ErrInvalidRdata)
+	}
+
+	result := make([]ResourceRecord, 0, len(values))
+	for _, value := range values {
+		rdata, err := octoDNSRdata(recordType, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", name, recordType, err)
+		}
+		result = append(result, ResourceRecord{
+			Name:   name,
+			Class:  defaultClass,
+			Type:   recordType,
+			TTL:    ttl,
+			HasTTL: true,
+			Values: rdata,
+		})
+	}
+	return result, nil
+}
+
+// octoDNSValue converts the values of a record into an octoDNS value.
+func octoDNSValue(rr ResourceRecord) (any, error) {
+	recordType := strings.ToUpper(rr.Type)
+	if slices.Contains(octoDNSTypes, recordType) == false || strings.EqualFold(rr.Class, defaultClass) == false {
+		return nil, ErrUnsupportedRecord
+	}
+	if len(rr.Values) == 0 {
+		return nil, fmt.Errorf("record has no values: %w", ErrInvalidRdata)
+	}
+
+	if isTextType(recordType) {
+		strs, err := parseCharacterStrings(rr.Values)
+		if err != nil {
+			return nil, err
+		}
+		// octoDNS joins the strings of a value, and requires semicolons to
+		// be escaped.
+		return strings.ReplaceAll(strings.Join(strs, ""), ";", `\;`), nil
+	}
+
+	schema, _ := rdataSchema(recordType)
+	if len(schema) == 1 {
+		if len(rr.Values) != 1 {
+			return nil, fmt.Errorf("expected 1 value: %w", ErrInvalidRdata)
+		}
+		return rr.Values[0], nil
+	}
+
+	if len(rr.Values) < len(schema) ||
+		(len(rr.Values) > len(schema) && schema[len(schema)-1].kind.consumesRemainder() == false) {
+		return nil, fmt.Errorf("expected %d values: %w", len(schema), ErrInvalidRdata)
+	}
+	result := make(map[string]any, len(schema))
+	for i, field := range schema {
+		switch field.kind {
+		case rdataUint8, rdataUint16, rdataUint32:
+			number, err := strconv.Atoi(rr.Values[i])
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a number: %w", field.name, ErrInvalidRdata)
+			}
+			result[field.name] = number
+		case rdataString:
+			str, err := parseCharacterString(rr.Values[i])
+			if err != nil {
+				return nil, err
+			}
+			result[field.name] = str
+		case rdataHex, rdataBase64:
+			result[field.name] = strings.Join(rr.Values[i:], "")
+		default:
+			result[field.name] = rr.Values[i]
+		}
+	}
+	return result, nil
+}
+
+// octoDNSRdata converts an octoDNS value into record values.
+func octoDNSRdata(recordType string, value any) ([]string, error) {
+	if isTextType(recordType) {
+		str, ok := value.(string)
+		if ok == false {
+			return nil, fmt.Errorf("expected a string value: %w", ErrInvalidRdata)
+		}
+		str = strings.ReplaceAll(str, `\;`, ";")
+		result := make([]string, 0)
+		for _, chunk := range splitCharacterString(str) {
+			result = append(result, quoteCharacterString(chunk))
+		}
+		return result, nil
+	}
+
+	schema, _ := rdataSchema(recordType)
+	if len(schema) == 1 {
+		if _, ok := value.(map[string]any); ok == true {
+			return nil, fmt.Errorf("expected a single value: %w", ErrInvalidRdata)
+		}
+		return []string{fmt.Sprint(value)}, nil
+	}
+
+	fields, ok := value.(map[string]any)
+	if ok == false {
+		return nil, fmt.Errorf("expected a map of %s fields: %w", recordType, ErrInvalidRdata)
+	}
+	result := make([]string, 0, len(schema))
+	for _, field := range schema {
+		v, ok := fields[field.name]
+		if ok == false {
+			v, ok = fields[octoDNSFieldAliases[recordType][field.name]]
+		}
+		if ok == false {
+			return nil, fmt.Errorf("missing field %s: %w", field.name, ErrInvalidRdata)
+		}
+		if field.kind == rdataString {
+			result = append(result, quoteCharacterString(fmt.Sprint(v)))
+			continue
+		}
+		result = append(result, fmt.Sprint(v))
+	}
+	return result, nil
+}
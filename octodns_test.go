@@ -0,0 +1,178 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_WriteOctoDNS(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 300 IN MX 10 mail
+@ 300 IN MX 20 mail2.example.net.
+@ 300 IN TXT "v=spf1 -all"
+www 300 IN A 192.0.2.1
+www 300 IN A 192.0.2.2
+_sip._tcp 300 IN SRV 10 5 5060 sip
+@ 300 IN CAA 0 issue "letsencrypt.org"
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteOctoDNS(&buf, z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, `"":
+  - type: CAA
+    ttl: 300
+    value:
+      flags: 0
+      tag: issue
+      value: letsencrypt.org
+  - type: MX
+    ttl: 300
+    values:
+      - exchange: mail.example.com.
+        preference: 10
+      - exchange: mail2.example.net.
+        preference: 20
+  - type: TXT
+    ttl: 300
+    value: v=spf1 -all
+_sip._tcp:
+  type: SRV
+  ttl: 300
+  value:
+    port: 5060
+    priority: 10
+    target: sip.example.com.
+    weight: 5
+www:
+  type: A
+  ttl: 300
+  values:
+    - 192.0.2.1
+    - 192.0.2.2
+`, buf.String())
+}
+
+func Test_WriteOctoDNS_Errors(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.1
+@ 300 IN HINFO "PC" "Linux"
+www.example.net. 300 IN A 192.0.2.1
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteOctoDNS(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.Empty(t, buf.String())
+}
+
+func Test_ParseOctoDNS(t *testing.T) {
+	document := `---
+'':
+  - type: MX
+    values:
+      - exchange: mx1.example.com.
+        preference: 10
+      - priority: 20
+        value: mx2.example.com.
+  - type: TXT
+    ttl: 600
+    value: v=DKIM1\; k=rsa\; p=abc
+  - type: CAA
+    value:
+      flags: 0
+      tag: issue
+      value: letsencrypt.org
+www:
+  type: CNAME
+  value: example.com.
+`
+	found, err := ParseOctoDNS(strings.NewReader(document), "example.com.")
+	require.Nil(t, err)
+	assert.Equal(t, []ResourceRecord{
+		{Name: "example.com.", Class: "IN", Type: "MX", TTL: 3600, HasTTL: true, Values: []string{"10", "mx1.example.com."}},
+		{Name: "example.com.", Class: "IN", Type: "MX", TTL: 3600, HasTTL: true, Values: []string{"20", "mx2.example.com."}},
+		{Name: "example.com.", Class: "IN", Type: "TXT", TTL: 600, HasTTL: true, Values: []string{`"v=DKIM1; k=rsa; p=abc"`}},
+		{Name: "example.com.", Class: "IN", Type: "CAA", TTL: 3600, HasTTL: true, Values: []string{"0", "issue", `"letsencrypt.org"`}},
+		{Name: "www.example.com.", Class: "IN", Type: "CNAME", TTL: 3600, HasTTL: true, Values: []string{"example.com."}},
+	}, found.Records)
+
+	_, err = ParseOctoDNS(strings.NewReader("www:\n  type: LOC\n  value: foo\n"), "example.com.")
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+
+	_, err = ParseOctoDNS(strings.NewReader("www:\n  type: MX\n  value:\n    preference: 10\n"), "example.com.")
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	assert.ErrorContains(t, err, "line 2")
+
+	_, err = ParseOctoDNS(strings.NewReader("- foo\n"), "example.com.")
+	assert.ErrorContains(t, err, "expected a map of names")
+}
+
+func Test_OctoDNS_RoundTrip(t *testing.T) {
+	tests := []struct {
+		dir  string
+		name string
+		apex string
+	}{
+		{"testdata", "simple.txt", "example.com."},
+		{"testdata", "opendkim_1024.sample.txt", "example.com."},
+		{"testdata", "opendkim_2048.sample.txt", "example.com."},
+		{"testdata/bind9", "master1.txt", "example.com."},
+		{"testdata/bind9", "master3.txt", "example.com."},
+		{"testdata/bind9", "master4.txt", "example.com."},
+		{"testdata/bind9", "master17.txt", "test."},
+		{"testdata/prefer_soa_min_ttl", "001.txt", "example.com."},
+	}
+
+	zp, _ := NewZoneParser()
+	for _, test := range tests {
+		t.Logf("testing fixture: %s/%s", test.dir, test.name)
+		fixtures, err := readFixtures(test.dir)
+		require.Nil(t, err)
+		expected, err := zp.Parse(fixtures[test.name].input)
+		require.Nil(t, err)
+		closeFixtures(fixtures)
+
+		buf := bytes.Buffer{}
+		err = WriteOctoDNS(&buf, expected, test.apex)
+		require.Nil(t, err)
+		found, err := ParseOctoDNS(&buf, test.apex)
+		require.Nil(t, err)
+
+		assert.Empty(t, Diff(semanticZone(expected, test.apex), semanticZone(found, test.apex)))
+	}
+
+	// Fixtures with records octoDNS cannot represent.
+	for _, name := range []string{"master2.txt", "master5.txt", "master6.txt"} {
+		fixtures, err := readFixtures("testdata/bind9")
+		require.Nil(t, err)
+		z, err := zp.Parse(fixtures[name].input)
+		require.Nil(t, err)
+		closeFixtures(fixtures)
+
+		err = WriteOctoDNS(&bytes.Buffer{}, z, "example.com.")
+		assert.Error(t, err, name)
+	}
+}
+
+// semanticZone normalizes a zone for comparison with a zone that has been
+// converted to another format and back. Names are qualified, TXT strings are
+// joined, and the SOA record is dropped.
+func semanticZone(z *Zone, apex string) *Zone {
+	result := &Zone{}
+	for _, rr := range z.Records {
+		rr = qualifyRdata(rr, apex)
+		rr.Name = qualifyName(rr.Name, apex)
+		if isTextType(rr.Type) {
+			strs, _ := parseCharacterStrings(rr.Values)
+			rr.Values = []string{quoteCharacterString(strings.Join(strs, ""))}
+		}
+		result.Records = append(result.Records, rr)
+	}
+	return result
+}
@@ -0,0 +1,460 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Policy is a set of rules that the records of a zone must follow, e.g. that
+// MX records only name approved mail servers. Policies are usually read from
+// YAML with [ParsePolicy]:
+//
+//	origin: example.com.
+//	rules:
+//	  - id: mx-allowlist
+//	    select: {type: MX}
+//	    assert:
+//	      field: exchange
+//	      in: [mx1.example.com., mx2.example.com.]
+//	  - id: no-private-addresses
+//	    severity: warning
+//	    select: {type: [A, AAAA]}
+//	    assert: {field: address, not_in_cidr: [10.0.0.0/8, fc00::/7]}
+//	  - id: dmarc-reject
+//	    select: {name: _dmarc.*, type: TXT}
+//	    assert: {field: text, matches: '\bp=reject\b'}
+type Policy struct {
+	// Origin qualifies relative names before rules are evaluated. When it
+	// is empty, the name of the SOA record is used if it is absolute.
+	Origin string `yaml:"origin"`
+
+	Rules []PolicyRule `yaml:"rules"`
+}
+
+// PolicyRule asserts something about every record that its selector
+// matches.
+type PolicyRule struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+
+	// Severity is the severity of the violations of the rule. It is
+	// [SeverityError] when nil, and [SeverityOff] disables the rule.
+	Severity *Severity `yaml:"severity"`
+
+	Select PolicySelector  `yaml:"select"`
+	Assert PolicyAssertion `yaml:"assert"`
+}
+
+// PolicySelector chooses the records that a rule applies to. Every field
+// that is not empty must match. An empty selector matches every record.
+type PolicySelector struct {
+	// Name holds glob patterns of owner names, in which `*` matches any
+	// characters, including dots. Names are compared case-insensitively.
+	Name PolicyList `yaml:"name"`
+
+	// Type holds record types, compared case-insensitively.
+	Type PolicyList `yaml:"type"`
+
+	// Value is a regular expression that the values of a record, joined
+	// by spaces, must match.
+	Value string `yaml:"value"`
+}
+
+// PolicyAssertion is a condition on one field of a record. Every condition
+// that is set must hold.
+type PolicyAssertion struct {
+	// Field names what is checked: `name`, `ttl`, `class`, `type`, `value`
+	// for all values joined by spaces, or a field of the RDATA of the
+	// selected records, e.g. `exchange` of MX records or `text` of TXT
+	// records. RDATA fields are named as in the RFC that defines the type,
+	// in lower snake case. The text of TXT records is the concatenation of
+	// its strings.
+	Field string `yaml:"field"`
+
+	// In and NotIn list the allowed, and forbidden, values. Domain names
+	// are compared case-insensitively.
+	In    PolicyList `yaml:"in"`
+	NotIn PolicyList `yaml:"not_in"`
+
+	// Matches and NotMatches are regular expressions that the value must,
+	// and must not, match.
+	Matches    string `yaml:"matches"`
+	NotMatches string `yaml:"not_matches"`
+
+	// InCIDR and NotInCIDR list the networks that an address must, and must
+	// not, be within.
+	InCIDR    PolicyList `yaml:"in_cidr"`
+	NotInCIDR PolicyList `yaml:"not_in_cidr"`
+
+	// Min and Max bound numeric values, e.g. the TTL.
+	Min *int `yaml:"min"`
+	Max *int `yaml:"max"`
+}
+
+// PolicyList is a list of strings that may be written in YAML as a single
+// scalar, e.g. `type: MX`, or as a sequence, e.g. `type: [A, AAAA]`.
+type PolicyList []string
+
+func (l *PolicyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = PolicyList{node.Value}
+		return nil
+	}
+	var values []string
+	err := node.Decode(&values)
+	if err != nil {
+		return err
+	}
+	*l = values
+	return nil
+}
+
+// ParsePolicy reads a [Policy] from YAML. Unknown keys, and rules that
+// cannot be evaluated, e.g. because of an invalid regular expression, are
+// reported as errors.
+func ParsePolicy(reader io.Reader) (*Policy, error) {
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+	result := &Policy{}
+	err := decoder.Decode(result)
+	if err != nil && errors.Is(err, io.EOF) == false {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	_, err = result.compile()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// compiledRule is a [PolicyRule] with its patterns and networks parsed.
+type compiledRule struct {
+	rule       PolicyRule
+	severity   Severity
+	names      []*regexp.Regexp
+	value      *regexp.Regexp
+	matches    *regexp.Regexp
+	notMatches *regexp.Regexp
+	inCIDR     []netip.Prefix
+	notInCIDR  []netip.Prefix
+}
+
+func (p *Policy) compile() ([]compiledRule, error) {
+	var errs []error
+	result := make([]compiledRule, 0, len(p.Rules))
+	ids := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if rule.ID == "" {
+			rule.ID = "rule-" + strconv.Itoa(i+1)
+		}
+		compiled, err := compileRule(rule)
+		if err == nil && ids[rule.ID] == true {
+			err = errors.New("duplicate id")
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("policy rule %s: %w", rule.ID, err))
+			continue
+		}
+		ids[rule.ID] = true
+		result = append(result, compiled)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return result, nil
+}
+
+func compileRule(rule PolicyRule) (compiledRule, error) {
+	result := compiledRule{rule: rule, severity: SeverityError}
+	if rule.Severity != nil {
+		result.severity = *rule.Severity
+	}
+	if result.severity < SeverityOff || result.severity > SeverityError {
+		return result, fmt.Errorf("unknown severity: %d", result.severity)
+	}
+	if rule.Assert.Field == "" {
+		return result, errors.New("assert requires a field")
+	}
+	if isRecordField(rule.Assert.Field) == false {
+		return result, fmt.Errorf("assert has an unknown field: %s", rule.Assert.Field)
+	}
+
+	var err error
+	for _, name := range rule.Select.Name {
+		result.names = append(result.names, globPattern(name, true))
+	}
+	compile := func(pattern string) *regexp.Regexp {
+		if pattern == "" || err != nil {
+			return nil
+		}
+		var re *regexp.Regexp
+		re, err = regexp.Compile(pattern)
+		return re
+	}
+	result.value = compile(rule.Select.Value)
+	result.matches = compile(rule.Assert.Matches)
+	result.notMatches = compile(rule.Assert.NotMatches)
+	if err != nil {
+		return result, err
+	}
+
+	parse := func(networks []string) []netip.Prefix {
+		prefixes := make([]netip.Prefix, 0, len(networks))
+		for _, network := range networks {
+			prefix, e := netip.ParsePrefix(network)
+			if e != nil && err == nil {
+				err = e
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		}
+		return prefixes
+	}
+	result.inCIDR = parse(rule.Assert.InCIDR)
+	result.notInCIDR = parse(rule.Assert.NotInCIDR)
+	return result, err
+}
+
+// globPattern converts a glob pattern into a regular expression that
+// matches whole values, case-insensitively when fold is `true`, as domain
+// names are compared.
+func globPattern(glob string, fold bool) *regexp.Regexp {
+	str := strings.Builder{}
+	str.WriteString("^(")
+	if fold == true {
+		str.WriteString("?i:")
+	}
+	for _, r := range glob {
+		switch r {
+		case '*':
+			str.WriteString(".*")
+		case '?':
+			str.WriteString(".")
+		default:
+			str.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	str.WriteString(")$")
+	return regexp.MustCompile(str.String())
+}
+
+// Evaluate checks every record of a zone, starting with its SOA record,
+// against the rules of the policy, except those whose severity is
+// [SeverityOff]. Violations are returned in the order of the rules, and
+// within a rule in the order of the records. An error is returned when the
+// policy cannot be evaluated, see [ParsePolicy].
+func (p *Policy) Evaluate(z *Zone) ([]Finding, error) {
+	rules, err := p.compile()
+	if err != nil {
+		return nil, err
+	}
+	origin := p.Origin
+	if origin == "" && isAbsoluteName(z.SOA.Name) {
+		origin = z.SOA.Name
+	}
+	qualified := qualifyZone(z, origin)
+	records := qualified.AllRecords()
+
+	result := make([]Finding, 0)
+	for _, rule := range rules {
+		if rule.severity == SeverityOff {
+			continue
+		}
+		for _, rr := range records {
+			if rule.selects(rr) == false {
+				continue
+			}
+			message, ok := rule.check(rr)
+			if ok == true {
+				continue
+			}
+			result = append(result, Finding{
+				Rule:     rule.rule.ID,
+				Severity: rule.severity,
+				Record:   rr,
+				Message:  message,
+			})
+		}
+	}
+	return result, nil
+}
+
+// selects determines if the selector of the rule matches a record.
+func (rule compiledRule) selects(rr ResourceRecord) bool {
+	selector := rule.rule.Select
+	if len(selector.Type) > 0 && containsFold(selector.Type, rr.Type) == false {
+		return false
+	}
+	if len(rule.names) > 0 {
+		found := false
+		for _, name := range rule.names {
+			found = found || name.MatchString(rr.Name)
+		}
+		if found == false {
+			return false
+		}
+	}
+	if rule.value != nil && rule.value.MatchString(strings.Join(rr.Values, " ")) == false {
+		return false
+	}
+	return true
+}
+
+// check determines if a record passes the assertion of the rule. A message
+// that describes the failure is returned when it does not.
+func (rule compiledRule) check(rr ResourceRecord) (string, bool) {
+	assert := rule.rule.Assert
+	value, isName, ok := policyField(rr, assert.Field)
+	if ok == false {
+		return fmt.Sprintf("record has no field %s", assert.Field), false
+	}
+	described := assert.Field + " " + value
+	if value == "" {
+		described = assert.Field + " is empty and"
+	}
+
+	equal := func(a string, b string) bool {
+		if isName == true {
+			return strings.EqualFold(fqdn(a), fqdn(b))
+		}
+		return a == b
+	}
+	contains := func(list []string) bool {
+		for _, v := range list {
+			if equal(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(assert.In) > 0 && contains(assert.In) == false {
+		return fmt.Sprintf("%s is not one of %s", described, strings.Join(assert.In, ", ")), false
+	}
+	if len(assert.NotIn) > 0 && contains(assert.NotIn) == true {
+		return fmt.Sprintf("%s is forbidden", described), false
+	}
+	if rule.matches != nil && rule.matches.MatchString(value) == false {
+		return fmt.Sprintf("%s does not match %s", described, assert.Matches), false
+	}
+	if rule.notMatches != nil && rule.notMatches.MatchString(value) == true {
+		return fmt.Sprintf("%s matches %s", described, assert.NotMatches), false
+	}
+
+	if len(rule.inCIDR) > 0 || len(rule.notInCIDR) > 0 {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return fmt.Sprintf("%s is not an address", described), false
+		}
+		addr = addr.Unmap()
+		within := func(prefixes []netip.Prefix) (netip.Prefix, bool) {
+			for _, prefix := range prefixes {
+				if prefix.Contains(addr) {
+					return prefix, true
+				}
+			}
+			return netip.Prefix{}, false
+		}
+		if _, ok := within(rule.inCIDR); len(rule.inCIDR) > 0 && ok == false {
+			return fmt.Sprintf("%s is not within %s", described, strings.Join(assert.InCIDR, ", ")), false
+		}
+		if prefix, ok := within(rule.notInCIDR); ok == true {
+			return fmt.Sprintf("%s is within %s", described, prefix), false
+		}
+	}
+
+	if assert.Min != nil || assert.Max != nil {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Sprintf("%s is not a number", described), false
+		}
+		if assert.Min != nil && number < *assert.Min {
+			return fmt.Sprintf("%s is below %d", described, *assert.Min), false
+		}
+		if assert.Max != nil && number > *assert.Max {
+			return fmt.Sprintf("%s is above %d", described, *assert.Max), false
+		}
+	}
+	return "", true
+}
+
+// policyField finds the value of a field of a record, see
+// [PolicyAssertion.Field]. It also reports whether the value is a domain
+// name. The third return value is false when the record has no such field.
+func policyField(rr ResourceRecord, field string) (string, bool, bool) {
+	switch field {
+	case "name":
+		return rr.Name, true, true
+	case "ttl":
+		return strconv.Itoa(rr.TTL), false, true
+	case "class":
+		return strings.ToUpper(rr.Class), false, true
+	case "type":
+		return strings.ToUpper(rr.Type), false, true
+	case "value":
+		return strings.Join(rr.Values, " "), false, true
+	}
+
+	schema, ok := rdataSchema(rr.Type)
+	if ok == false || isGenericRdata(rr.Values) {
+		return "", false, false
+	}
+	for i, f := range schema {
+		if f.name != field {
+			continue
+		}
+		if i >= len(rr.Values) {
+			return "", f.kind.isName(), true
+		}
+		if f.kind == rdataStrings {
+			strs, err := parseCharacterStrings(rr.Values[i:])
+			if err != nil {
+				return strings.Join(rr.Values[i:], " "), false, true
+			}
+			return strings.Join(strs, ""), false, true
+		}
+		if f.kind == rdataString {
+			str, err := parseCharacterString(rr.Values[i])
+			if err == nil {
+				return str, false, true
+			}
+		}
+		if f.kind.consumesRemainder() {
+			return strings.Join(rr.Values[i:], " "), false, true
+		}
+		return rr.Values[i], f.kind.isName(), true
+	}
+	return "", false, false
+}
+
+// isRecordField determines if a field is known to [policyField], either as
+// a field of every record, or as a field of the RDATA of any type.
+func isRecordField(field string) bool {
+	switch field {
+	case "name", "ttl", "class", "type", "value":
+		return true
+	}
+	for _, schema := range rdataSchemas {
+		for _, f := range schema {
+			if f.name == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsFold determines if a list holds a string, compared
+// case-insensitively.
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
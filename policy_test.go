@@ -0,0 +1,118 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const samplePolicy = `origin: example.com.
+rules:
+  - id: mx-allowlist
+    description: mail is only accepted by our servers
+    select: {type: MX}
+    assert:
+      field: exchange
+      in: [mx1.example.com., MX2.example.com]
+  - id: no-private-addresses
+    severity: warning
+    select: {type: [A, AAAA], name: "*.example.com."}
+    assert: {field: address, not_in_cidr: [10.0.0.0/8, fc00::/7]}
+  - id: dmarc-reject
+    select: {name: _dmarc.*, type: txt}
+    assert: {field: text, matches: '\bp=reject\b'}
+  - id: ttl-floor
+    severity: info
+    select: {value: '^192\.0\.2\.'}
+    assert: {field: ttl, min: 300}
+  - id: disabled
+    severity: off
+    assert: {field: ttl, max: 0}
+`
+
+func Test_Policy_Evaluate(t *testing.T) {
+	policy, err := ParsePolicy(strings.NewReader(samplePolicy))
+	require.Nil(t, err)
+	require.Len(t, policy.Rules, 5)
+	assert.Equal(t, SeverityOff, *policy.Rules[4].Severity)
+	assert.Nil(t, policy.Rules[0].Severity)
+
+	z := mustParse(t, `$ORIGIN example.com.
+@ 300 IN MX 10 mx1
+@ 300 IN MX 20 mx2.example.com.
+@ 300 IN MX 30 mail.example.net.
+www 300 IN A 192.0.2.1
+intranet 300 IN A 10.1.2.3
+v6 300 IN AAAA fd00::1
+_dmarc 300 IN TXT "v=DMARC1; " "p=reject"
+_dmarc.sub 300 IN TXT "v=DMARC1; p=none"
+short 60 IN A 192.0.2.2
+`)
+	violations, err := policy.Evaluate(z)
+	require.Nil(t, err)
+	found := make([]string, 0)
+	for _, v := range violations {
+		found = append(found, v.String())
+	}
+	assert.Equal(t, []string{
+		"4: error: example.com. MX: exchange mail.example.net. is not one of mx1.example.com., MX2.example.com (mx-allowlist)",
+		"6: warning: intranet.example.com. A: address 10.1.2.3 is within 10.0.0.0/8 (no-private-addresses)",
+		"7: warning: v6.example.com. AAAA: address fd00::1 is within fc00::/7 (no-private-addresses)",
+		`9: error: _dmarc.sub.example.com. TXT: text v=DMARC1; p=none does not match \bp=reject\b (dmarc-reject)`,
+		"10: info: short.example.com. A: ttl 60 is below 300 (ttl-floor)",
+	}, found)
+}
+
+func Test_Policy_Fields(t *testing.T) {
+	policy := &Policy{Rules: []PolicyRule{
+		{ID: "srv-target", Select: PolicySelector{Type: PolicyList{"SRV"}}, Assert: PolicyAssertion{Field: "target", NotIn: PolicyList{"old.example.com."}}},
+		{ID: "caa-issuer", Select: PolicySelector{Type: PolicyList{"CAA"}}, Assert: PolicyAssertion{Field: "value", Matches: "letsencrypt"}},
+		{ID: "caa-value", Select: PolicySelector{Type: PolicyList{"CAA"}}, Assert: PolicyAssertion{Field: "value", NotMatches: `"`}},
+		{ID: "no-field", Select: PolicySelector{Type: PolicyList{"A"}}, Assert: PolicyAssertion{Field: "exchange", In: PolicyList{"x"}}},
+	}}
+	z := &Zone{Records: []ResourceRecord{
+		{Name: "_sip._tcp.example.com.", Type: "SRV", Values: []string{"10", "5", "5060", "OLD.example.com."}},
+		{Name: "example.com.", Type: "CAA", Values: []string{"0", "issue", `"letsencrypt.org"`}},
+		{Name: "www.example.com.", Type: "A", Values: []string{"192.0.2.1"}},
+	}}
+	violations, err := policy.Evaluate(z)
+	require.Nil(t, err)
+	require.Len(t, violations, 3)
+	assert.Equal(t, "error: _sip._tcp.example.com. SRV: target OLD.example.com. is forbidden (srv-target)", violations[0].String())
+	assert.Equal(t, "caa-value", violations[1].Rule)
+	assert.Equal(t, "error: www.example.com. A: record has no field exchange (no-field)", violations[2].String())
+
+	value, isName, ok := policyField(z.Records[1], "value")
+	assert.Equal(t, `0 issue "letsencrypt.org"`, value)
+	assert.False(t, isName)
+	assert.True(t, ok)
+	value, _, ok = policyField(z.Records[1], "tag")
+	assert.Equal(t, "issue", value)
+	assert.True(t, ok)
+}
+
+func Test_ParsePolicy_Errors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "rules:\n  - id: a\n    assert: {field: name, matches: '('}\n", expected: "policy rule a: error parsing regexp"},
+		{input: "rules:\n  - id: a\n    assert: {field: address, in_cidr: [10.0.0.0]}\n", expected: `policy rule a: netip.ParsePrefix("10.0.0.0")`},
+		{input: "rules:\n  - select: {type: A}\n", expected: "policy rule rule-1: assert requires a field"},
+		{input: "rules:\n  - {id: a, select: {type: MX}, assert: {field: exchnage, in: [mx.example.com.]}}\n", expected: "policy rule a: assert has an unknown field: exchnage"},
+		{input: "rules:\n  - {id: a, assert: {field: ttl}}\n  - {id: a, assert: {field: ttl}}\n", expected: "policy rule a: duplicate id"},
+		{input: "rules:\n  - {id: a, severity: loud, assert: {field: ttl}}\n", expected: "unknown severity: loud"},
+		{input: "rulez: []\n", expected: "field rulez not found"},
+	}
+	for _, test := range tests {
+		_, err := ParsePolicy(strings.NewReader(test.input))
+		assert.ErrorContains(t, err, test.expected, test.input)
+	}
+
+	policy, err := ParsePolicy(strings.NewReader(""))
+	require.Nil(t, err)
+	violations, err := policy.Evaluate(&Zone{})
+	require.Nil(t, err)
+	assert.Len(t, violations, 0)
+}
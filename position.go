@@ -0,0 +1,48 @@
+package zone
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Position identifies where in a source a [ResourceRecord] was read from.
+type Position struct {
+	// File is the name of the source as given by [WithFileName]. It is
+	// empty when no name was provided.
+	File string
+
+	// StartLine is the 1-based line number on which the record begins.
+	StartLine int
+
+	// EndLine is the line number on which the record ends. It will differ
+	// from StartLine when the record was continued across lines with
+	// parentheses.
+	EndLine int
+}
+
+// IsValid indicates if the position refers to an actual source line.
+// Records that were not read by [ZoneParser.Parse] have an invalid position.
+func (p Position) IsValid() bool {
+	return p.StartLine > 0
+}
+
+// String renders the position as `file:start-end`. The file name is omitted
+// when it is unknown, and the end line is omitted when the record occupies
+// a single line.
+//
+//	fmt.Println(Position{File: "db.example", StartLine: 3, EndLine: 8}) // db.example:3-8
+func (p Position) String() string {
+	if p.IsValid() == false {
+		return ""
+	}
+
+	str := strings.Builder{}
+	if p.File != "" {
+		str.WriteString(p.File + ":")
+	}
+	str.WriteString(strconv.Itoa(p.StartLine))
+	if p.EndLine > p.StartLine {
+		str.WriteString("-" + strconv.Itoa(p.EndLine))
+	}
+	return str.String()
+}
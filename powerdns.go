@@ -0,0 +1,108 @@
+package zone
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// PowerDNSPatch is the body of a PowerDNS HTTP API
+// [PATCH /servers/{server_id}/zones/{zone_id}] request.
+//
+// [PATCH /servers/{server_id}/zones/{zone_id}]: https://doc.powerdns.com/authoritative/http-api/zone.html#patch--servers-server_id-zones-zone_id
+type PowerDNSPatch struct {
+	RRSets []PowerDNSRRSet `json:"rrsets"`
+}
+
+type PowerDNSRRSet struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	TTL  int    `json:"ttl"`
+
+	// ChangeType is either `REPLACE` or `DELETE`.
+	ChangeType string `json:"changetype"`
+
+	// Records is empty when ChangeType is `DELETE`.
+	Records []PowerDNSRecord `json:"records"`
+}
+
+type PowerDNSRecord struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+// powerDNSTypes lists the record types that PowerDNS supports.
+var powerDNSTypes = []string{
+	"A", "AAAA", "AFSDB", "ALIAS", "APL", "CAA", "CDNSKEY", "CDS", "CERT",
+	"CNAME", "CSYNC", "DHCID", "DNAME", "DNSKEY", "DS", "HINFO", "HTTPS", "KEY",
+	"LOC", "MX", "NAPTR", "NS", "NSEC", "NSEC3", "NSEC3PARAM", "OPENPGPKEY",
+	"PTR", "RP", "RRSIG", "SMIMEA", "SOA", "SPF", "SRV", "SSHFP", "SVCB",
+	"TLSA", "TXT", "URI", "ZONEMD",
+}
+
+// PowerDNSZonePatch converts every record set of a zone, including the SOA
+// record, into a PowerDNS patch that replaces the sets of the zone at apex.
+// It is equivalent to `PowerDNSDiffPatch(Diff(nil, z), apex)`.
+func PowerDNSZonePatch(z *Zone, apex string) (PowerDNSPatch, error) {
+	return PowerDNSDiffPatch(Diff(nil, z), apex)
+}
+
+// PowerDNSDiffPatch converts record set changes, as produced by [Diff], into
+// a PowerDNS patch for the zone at apex. Added and updated sets are
+// replaced, and deleted sets are deleted.
+//
+// Owner names and domain names within record contents are qualified with
+// apex. Contents are formatted as PowerDNS expects, e.g. MX contents
+// include the preference and every TXT string is quoted.
+//
+// Records of a type PowerDNS does not support, of a class other than IN,
+// or that are outside apex, are reported as [RecordError] values in the
+// returned error.
+func PowerDNSDiffPatch(changes []RRSetChange, apex string) (PowerDNSPatch, error) {
+	apex = fqdn(apex)
+
+	var errs []error
+	result := PowerDNSPatch{
+		RRSets: make([]PowerDNSRRSet, 0, len(changes)),
+	}
+	for _, change := range changes {
+		set := change.New
+		if change.Kind == ChangeDelete {
+			set = change.Old
+		}
+		rrset := PowerDNSRRSet{
+			Name:       qualifyName(set.Name, apex),
+			Type:       strings.ToUpper(set.Type),
+			TTL:        set.TTL,
+			ChangeType: "REPLACE",
+			Records:    make([]PowerDNSRecord, 0, len(set.Records)),
+		}
+		if change.Kind == ChangeDelete {
+			rrset.ChangeType = "DELETE"
+		}
+
+		for _, rr := range set.Records {
+			var err error
+			var content string
+			if isInZone(rrset.Name, apex) == false {
+				err = ErrOutOfZone
+			} else if slices.Contains(powerDNSTypes, rrset.Type) == false || strings.EqualFold(rr.Class, defaultClass) == false {
+				err = ErrUnsupportedRecord
+			} else {
+				content, err = formatRdata(qualifyRdata(rr, apex))
+			}
+			if err != nil {
+				errs = append(errs, &RecordError{Record: rr, Err: err})
+				continue
+			}
+			if change.Kind != ChangeDelete {
+				rrset.Records = append(rrset.Records, PowerDNSRecord{Content: content})
+			}
+		}
+		result.RRSets = append(result.RRSets, rrset)
+	}
+	if len(errs) > 0 {
+		return PowerDNSPatch{}, errors.Join(errs...)
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package zone
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_PowerDNSZonePatch(t *testing.T) {
+	fixtures, err := readFixtures("testdata")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	zp, _ := NewZoneParser()
+	z, err := zp.Parse(fixtures["simple.txt"].input)
+	require.Nil(t, err)
+
+	found, err := PowerDNSZonePatch(z, "example.com")
+	require.Nil(t, err)
+	require.Len(t, found.RRSets, 11)
+	assert.Equal(t, PowerDNSRRSet{
+		Name:       "example.com.",
+		Type:       "SOA",
+		TTL:        86400,
+		ChangeType: "REPLACE",
+		Records: []PowerDNSRecord{
+			{Content: "dns1.example.com. hostmaster.example.com. 2001062501 21600 3600 604800 86400"},
+		},
+	}, found.RRSets[0])
+	assert.Equal(t, PowerDNSRRSet{
+		Name:       "example.com.",
+		Type:       "MX",
+		TTL:        86400,
+		ChangeType: "REPLACE",
+		Records: []PowerDNSRecord{
+			{Content: "10 mail.example.com."},
+			{Content: "20 mail2.example.com."},
+		},
+	}, found.RRSets[2])
+
+	data, err := json.Marshal(found.RRSets[10])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"name": "www.example.com.",
+		"type": "CNAME",
+		"ttl": 86400,
+		"changetype": "REPLACE",
+		"records": [{"content": "server1.example.com.", "disabled": false}]
+	}`, string(data))
+}
+
+func Test_PowerDNSDiffPatch(t *testing.T) {
+	old := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.1
+ftp 300 IN A 192.0.2.3
+`)
+	new := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.2
+@ 300 IN TXT "v=spf1" "-all"
+@ 300 IN CAA 0 issue letsencrypt.org
+`)
+
+	found, err := PowerDNSDiffPatch(Diff(old, new), "example.com.")
+	require.Nil(t, err)
+
+	data, err := json.Marshal(found)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"rrsets": [
+		{"name": "ftp.example.com.", "type": "A", "ttl": 300, "changetype": "DELETE", "records": []},
+		{"name": "www.example.com.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": [
+			{"content": "192.0.2.2", "disabled": false}
+		]},
+		{"name": "example.com.", "type": "TXT", "ttl": 300, "changetype": "REPLACE", "records": [
+			{"content": "\"v=spf1\" \"-all\"", "disabled": false}
+		]},
+		{"name": "example.com.", "type": "CAA", "ttl": 300, "changetype": "REPLACE", "records": [
+			{"content": "0 issue \"letsencrypt.org\"", "disabled": false}
+		]}
+	]}`, string(data))
+
+	new = mustParse(t, `$ORIGIN example.com.
+www.example.net. 300 IN A 192.0.2.1
+www 300 CH TXT "foo"
+`)
+	_, err = PowerDNSDiffPatch(Diff(nil, new), "example.com.")
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+}
@@ -0,0 +1,75 @@
+package zone
+
+// ValueSource describes where the parser obtained the value of a record
+// field that may be omitted from a record line.
+type ValueSource int
+
+const (
+	// SourceAbsent indicates that no value was derived for the field, e.g.
+	// because it was not present on the line, or because the owner is `@`
+	// and no origin is known.
+	SourceAbsent ValueSource = iota
+
+	// SourceLine indicates the value was written on the record's own line.
+	SourceLine
+
+	// SourcePrevious indicates the value was inherited from the previous
+	// record, e.g. a line with a blank owner field.
+	SourcePrevious
+
+	// SourceOrigin indicates the value was taken from the `$ORIGIN` in
+	// effect, e.g. an owner of `@`.
+	SourceOrigin
+
+	// SourceTtlDirective indicates the value was taken from a `$TTL`
+	// directive.
+	SourceTtlDirective
+
+	// SourceSoaMinimum indicates the value was taken from the minimum TTL
+	// field of the SOA record. See [WithPreferSoaMinTtl].
+	SourceSoaMinimum
+
+	// SourceDefault indicates the parser's default value was used. See
+	// [WithDefaultTtl].
+	SourceDefault
+)
+
+func (vs ValueSource) String() string {
+	switch vs {
+	case SourceAbsent:
+		return "absent"
+	case SourceLine:
+		return "line"
+	case SourcePrevious:
+		return "previous record"
+	case SourceOrigin:
+		return "$ORIGIN"
+	case SourceTtlDirective:
+		return "$TTL"
+	case SourceSoaMinimum:
+		return "SOA minimum"
+	case SourceDefault:
+		return "default"
+	}
+	return "unknown"
+}
+
+// Provenance explains how the parser derived the owner name, TTL, and class
+// of a [ResourceRecord]. It is only recorded when the parser has been
+// created with [WithProvenance].
+type Provenance struct {
+	Name  ValueSource
+	TTL   ValueSource
+	Class ValueSource
+
+	// Origin is the `$ORIGIN` that was in effect when the record was parsed.
+	Origin string
+
+	// Qualified indicates that the owner name was relative and has had
+	// Origin appended to it.
+	Qualified bool
+
+	// Previous is the position of the record that any [SourcePrevious]
+	// values were inherited from.
+	Previous Position
+}
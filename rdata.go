@@ -0,0 +1,199 @@
+package zone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rdataKind classifies a field of the RDATA of a record in presentation
+// format.
+type rdataKind int
+
+const (
+	// rdataToken is a single field that has no further interpretation, e.g.
+	// the tag of a CAA record.
+	rdataToken rdataKind = iota
+
+	// rdataDomain is a domain name, e.g. the exchange of an MX record.
+	rdataDomain
+
+	// rdataMailbox is a domain name that encodes a mailbox, e.g. the RNAME of
+	// a SOA record.
+	rdataMailbox
+
+	rdataUint8
+	rdataUint16
+	rdataUint32
+	rdataIPv4
+	rdataIPv6
+
+	// rdataString is a single <character-string>.
+	rdataString
+
+	// rdataStrings is one or more <character-string> fields. It consumes all
+	// remaining values.
+	rdataStrings
+
+	// rdataBase64 is base64 encoded data that may be split across all
+	// remaining values.
+	rdataBase64
+
+	// rdataHex is hexadecimal data that may be split across all remaining
+	// values.
+	rdataHex
+
+	// rdataRemainder is free-form data that consumes all remaining values.
+	rdataRemainder
+)
+
+// consumesRemainder indicates that a field of the kind absorbs every
+// remaining value of a record.
+func (k rdataKind) consumesRemainder() bool {
+	switch k {
+	case rdataStrings, rdataBase64, rdataHex, rdataRemainder:
+		return true
+	}
+	return false
+}
+
+// isName indicates that a field of the kind holds a domain name.
+func (k rdataKind) isName() bool {
+	return k == rdataDomain || k == rdataMailbox
+}
+
+// rdataField describes one field of a record's RDATA. The names follow the
+// RFC that defines the record type, in lower snake case.
+type rdataField struct {
+	name string
+	kind rdataKind
+}
+
+// rdataSchemas describes the RDATA fields of the record types whose
+// presentation format is understood.
+var rdataSchemas = map[string][]rdataField{
+	"A":          {{"address", rdataIPv4}},
+	"AAAA":       {{"address", rdataIPv6}},
+	"AFSDB":      {{"subtype", rdataUint16}, {"hostname", rdataDomain}},
+	"CAA":        {{"flags", rdataUint8}, {"tag", rdataToken}, {"value", rdataString}},
+	"CDNSKEY":    dnskeySchema,
+	"CDS":        dsSchema,
+	"CERT":       {{"type", rdataToken}, {"key_tag", rdataUint16}, {"algorithm", rdataToken}, {"certificate", rdataBase64}},
+	"CNAME":      {{"target", rdataDomain}},
+	"CSYNC":      {{"serial", rdataUint32}, {"flags", rdataUint16}, {"types", rdataRemainder}},
+	"DHCID":      {{"digest", rdataBase64}},
+	"DLV":        dsSchema,
+	"DNAME":      {{"target", rdataDomain}},
+	"DNSKEY":     dnskeySchema,
+	"DS":         dsSchema,
+	"EUI48":      {{"address", rdataToken}},
+	"EUI64":      {{"address", rdataToken}},
+	"HINFO":      {{"cpu", rdataString}, {"os", rdataString}},
+	"HTTPS":      svcbSchema,
+	"KEY":        dnskeySchema,
+	"KX":         {{"preference", rdataUint16}, {"exchanger", rdataDomain}},
+	"LOC":        {{"location", rdataRemainder}},
+	"MB":         {{"madname", rdataDomain}},
+	"MD":         {{"madname", rdataDomain}},
+	"MF":         {{"madname", rdataDomain}},
+	"MG":         {{"mgmname", rdataMailbox}},
+	"MINFO":      {{"rmailbx", rdataMailbox}, {"emailbx", rdataMailbox}},
+	"MR":         {{"newname", rdataMailbox}},
+	"MX":         {{"preference", rdataUint16}, {"exchange", rdataDomain}},
+	"NAPTR":      {{"order", rdataUint16}, {"preference", rdataUint16}, {"flags", rdataString}, {"service", rdataString}, {"regexp", rdataString}, {"replacement", rdataDomain}},
+	"NS":         {{"host", rdataDomain}},
+	"NSEC":       {{"next_domain", rdataDomain}, {"types", rdataRemainder}},
+	"OPENPGPKEY": {{"public_key", rdataBase64}},
+	"PTR":        {{"target", rdataDomain}},
+	"PX":         {{"preference", rdataUint16}, {"map822", rdataDomain}, {"mapx400", rdataDomain}},
+	"RP":         {{"mbox", rdataMailbox}, {"txt", rdataDomain}},
+	"RT":         {{"preference", rdataUint16}, {"host", rdataDomain}},
+	"SMIMEA":     tlsaSchema,
+	"SOA":        {{"mname", rdataDomain}, {"rname", rdataMailbox}, {"serial", rdataUint32}, {"refresh", rdataUint32}, {"retry", rdataUint32}, {"expire", rdataUint32}, {"minimum", rdataUint32}},
+	"SPF":        {{"text", rdataStrings}},
+	"SRV":        {{"priority", rdataUint16}, {"weight", rdataUint16}, {"port", rdataUint16}, {"target", rdataDomain}},
+	"SSHFP":      {{"algorithm", rdataUint8}, {"fingerprint_type", rdataUint8}, {"fingerprint", rdataHex}},
+	"SVCB":       svcbSchema,
+	"TLSA":       tlsaSchema,
+	"TXT":        {{"text", rdataStrings}},
+	"URI":        {{"priority", rdataUint16}, {"weight", rdataUint16}, {"target", rdataString}},
+	"ZONEMD":     {{"serial", rdataUint32}, {"scheme", rdataUint8}, {"hash_algorithm", rdataUint8}, {"digest", rdataHex}},
+}
+
+var dnskeySchema = []rdataField{{"flags", rdataUint16}, {"protocol", rdataUint8}, {"algorithm", rdataUint8}, {"public_key", rdataBase64}}
+var dsSchema = []rdataField{{"key_tag", rdataUint16}, {"algorithm", rdataUint8}, {"digest_type", rdataUint8}, {"digest", rdataHex}}
+var svcbSchema = []rdataField{{"priority", rdataUint16}, {"target", rdataDomain}, {"params", rdataRemainder}}
+var tlsaSchema = []rdataField{{"certificate_usage", rdataUint8}, {"selector", rdataUint8}, {"matching_type", rdataUint8}, {"certificate_association_data", rdataHex}}
+
+// rdataSchema finds the RDATA fields of a record type.
+func rdataSchema(recordType string) ([]rdataField, bool) {
+	schema, ok := rdataSchemas[strings.ToUpper(recordType)]
+	return schema, ok
+}
+
+// rdataFieldAt finds the field that describes the value at index i of a
+// record of the given type.
+func rdataFieldAt(schema []rdataField, i int) (rdataField, bool) {
+	if i < len(schema) {
+		return schema[i], true
+	}
+	if len(schema) > 0 && schema[len(schema)-1].kind.consumesRemainder() {
+		return schema[len(schema)-1], true
+	}
+	return rdataField{}, false
+}
+
+// qualifyRdata returns a copy of the record in which every relative domain
+// name in its values has been qualified with origin. Values of unknown
+// record types, and values in the RFC 3597 generic form, are not changed.
+func qualifyRdata(rr ResourceRecord, origin string) ResourceRecord {
+	schema, ok := rdataSchema(rr.Type)
+	if ok == false || isGenericRdata(rr.Values) || origin == "" {
+		return rr
+	}
+
+	values := make([]string, len(rr.Values))
+	for i, v := range rr.Values {
+		values[i] = v
+		if field, ok := rdataFieldAt(schema, i); ok == true && field.kind.isName() {
+			values[i] = qualifyName(v, origin)
+		}
+	}
+	rr.Values = values
+	return rr
+}
+
+// formatRdata renders the values of a record as the single string that DNS
+// provider APIs expect. Every TXT string is quoted, and strings longer than
+// 255 bytes are split. The value of a CAA record is quoted.
+func formatRdata(rr ResourceRecord) (string, error) {
+	if len(rr.Values) == 0 {
+		return "", fmt.Errorf("record has no values: %w", ErrInvalidRdata)
+	}
+
+	switch strings.ToUpper(rr.Type) {
+	case "TXT", "SPF":
+		strs, err := parseCharacterStrings(rr.Values)
+		if err != nil {
+			return "", err
+		}
+		quoted := make([]string, 0, len(strs))
+		for _, str := range strs {
+			for _, chunk := range splitCharacterString(str) {
+				quoted = append(quoted, quoteCharacterString(chunk))
+			}
+		}
+		return strings.Join(quoted, " "), nil
+
+	case "CAA":
+		if len(rr.Values) != 3 {
+			return "", fmt.Errorf("CAA records require 3 values: %w", ErrInvalidRdata)
+		}
+		value, err := parseCharacterString(rr.Values[2])
+		if err != nil {
+			return "", err
+		}
+		return rr.Values[0] + " " + rr.Values[1] + " " + quoteCharacterString(value), nil
+	}
+
+	return strings.Join(rr.Values, " "), nil
+}
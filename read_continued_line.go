@@ -10,22 +10,31 @@ import (
 // opening parentheses is found with no closing parentheses on the same line.
 // For example, if the data stream contains `foo (\n bar\n baz)` then the
 // currentLine would be `foo (` and the result of this function will be
-// `foo ( bar baz)`.
-func readContinuedLine(reader io.Reader, currentLine []byte) ([]byte, error) {
+// `foo ( bar baz)`. The comments of the additional lines, and the number of
+// additional lines read, are also returned.
+func readContinuedLine(reader io.Reader, currentLine []byte) ([]byte, []string, int, error) {
 	r := bufio.NewReader(reader)
 	currentLine = compactWhiteSpace(stripComment(currentLine))
+	comments := make([]string, 0)
+	count := 0
 	for {
 		line, err := r.ReadBytes('\n')
 		if err != nil {
-			return nil, err
+			return nil, comments, count, err
+		}
+		count += 1
+		if comment, ok := lineComment(line); ok == true {
+			comments = append(comments, comment)
 		}
 
-		endIdx := lastIndexNonEscapedByte(line, byte(')'))
+		// Comments may include parentheses, e.g. `; refresh (2 hours)` as
+		// written by `dig +multi`, so they are removed first.
 		line = compactWhiteSpace(stripComment(line))
+		endIdx := lastIndexNonEscapedByte(line, byte(')'))
 		currentLine = append(currentLine, line...)
 		if endIdx > 0 {
 			break
 		}
 	}
-	return currentLine, nil
+	return currentLine, comments, count, nil
 }
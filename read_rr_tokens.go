@@ -10,6 +10,7 @@ func readRRTokens(tokens [][]byte, rr *ResourceRecord) {
 		if isTtlToken.Match(tokens[1]) {
 			// <class> <ttl> <type> <data>
 			rr.TTL = cast.ToInt(string(tokens[1]))
+			rr.HasTTL = true
 			rr.Type = string(tokens[2])
 			for _, t := range tokens[3:] {
 				rr.Values = append(rr.Values, string(t))
@@ -23,6 +24,7 @@ func readRRTokens(tokens [][]byte, rr *ResourceRecord) {
 		}
 	} else if isTtlToken.Match(tokens[0]) {
 		rr.TTL = cast.ToInt(string(tokens[0]))
+		rr.HasTTL = true
 		if isClassToken.Match(tokens[1]) {
 			// <ttl> <class> <type> <data>
 			rr.Class = string(tokens[1])
@@ -2,12 +2,16 @@ package zone
 
 import (
 	"slices"
+	"strconv"
 	"strings"
 )
 
 // recordTypes is a list of the known record types from
 // https://en.wikipedia.org/wiki/List_of_DNS_record_types
-var recordTypes = []string{
+var recordTypes = append(slices.Clone(commonRecordTypes), obscureRecordTypes...)
+
+// commonRecordTypes lists the record types in active use.
+var commonRecordTypes = []string{
 	"A",
 	"AAAA",
 	"AFSDB",
@@ -47,6 +51,7 @@ var recordTypes = []string{
 	"SOA",
 	"SRV",
 	"SSHFP",
+	"SVCB",
 	"TA",
 	"TKEY",
 	"TLSA",
@@ -54,7 +59,11 @@ var recordTypes = []string{
 	"TXT",
 	"URI",
 	"ZONEMD",
-	// Obscure list
+}
+
+// obscureRecordTypes lists the record types that are obsolete,
+// experimental, or were never widely deployed.
+var obscureRecordTypes = []string{
 	"MD",
 	"MF",
 	"MAILA",
@@ -100,3 +109,149 @@ func isRecordType(input []byte) bool {
 	str := strings.ToUpper(string(input))
 	return slices.Contains(recordTypes, str)
 }
+
+// recordTypeCodes maps record type mnemonics to their numeric values from
+// https://www.iana.org/assignments/dns-parameters/dns-parameters.xhtml#dns-parameters-4
+var recordTypeCodes = map[string]uint16{
+	"A":          1,
+	"NS":         2,
+	"MD":         3,
+	"MF":         4,
+	"CNAME":      5,
+	"SOA":        6,
+	"MB":         7,
+	"MG":         8,
+	"MR":         9,
+	"NULL":       10,
+	"WKS":        11,
+	"PTR":        12,
+	"HINFO":      13,
+	"MINFO":      14,
+	"MX":         15,
+	"TXT":        16,
+	"RP":         17,
+	"AFSDB":      18,
+	"X25":        19,
+	"ISDN":       20,
+	"RT":         21,
+	"NSAP":       22,
+	"NSAP-PTR":   23,
+	"SIG":        24,
+	"KEY":        25,
+	"PX":         26,
+	"GPOS":       27,
+	"AAAA":       28,
+	"LOC":        29,
+	"NXT":        30,
+	"EID":        31,
+	"NIMLOC":     32,
+	"SRV":        33,
+	"ATMA":       34,
+	"NAPTR":      35,
+	"KX":         36,
+	"CERT":       37,
+	"A6":         38,
+	"DNAME":      39,
+	"SINK":       40,
+	"APL":        42,
+	"DS":         43,
+	"SSHFP":      44,
+	"IPSECKEY":   45,
+	"RRSIG":      46,
+	"NSEC":       47,
+	"DNSKEY":     48,
+	"DHCID":      49,
+	"NSEC3":      50,
+	"NSEC3PARAM": 51,
+	"TLSA":       52,
+	"SMIMEA":     53,
+	"HIP":        55,
+	"NINFO":      56,
+	"RKEY":       57,
+	"TALINK":     58,
+	"CDS":        59,
+	"CDNSKEY":    60,
+	"OPENPGPKEY": 61,
+	"CSYNC":      62,
+	"ZONEMD":     63,
+	"SVCB":       64,
+	"HTTPS":      65,
+	"SPF":        99,
+	"UINFO":      100,
+	"UID":        101,
+	"GID":        102,
+	"UNSPEC":     103,
+	"NID":        104,
+	"L32":        105,
+	"L64":        106,
+	"LP":         107,
+	"EUI48":      108,
+	"EUI64":      109,
+	"TKEY":       249,
+	"TSIG":       250,
+	"MAILB":      253,
+	"MAILA":      254,
+	"URI":        256,
+	"CAA":        257,
+	"DOA":        259,
+	"TA":         32768,
+	"DLV":        32769,
+}
+
+// classCodes maps class mnemonics to their numeric values from
+// https://www.iana.org/assignments/dns-parameters/dns-parameters.xhtml#dns-parameters-2
+var classCodes = map[string]uint16{
+	"IN":  1,
+	"CS":  2,
+	"CH":  3,
+	"HS":  4,
+	"ANY": 255,
+}
+
+// recordTypeCode resolves a record type mnemonic, or an RFC 3597 `TYPEnnn`
+// identifier, to its numeric value.
+func recordTypeCode(name string) (uint16, bool) {
+	return mnemonicCode(recordTypeCodes, "TYPE", name)
+}
+
+// recordTypeName resolves a numeric record type to its mnemonic. Types
+// without a mnemonic are rendered in the RFC 3597 `TYPEnnn` form.
+func recordTypeName(code uint16) string {
+	return codeMnemonic(recordTypeCodes, "TYPE", code)
+}
+
+// classCode resolves a class mnemonic, or an RFC 3597 `CLASSnnn` identifier,
+// to its numeric value.
+func classCode(name string) (uint16, bool) {
+	return mnemonicCode(classCodes, "CLASS", name)
+}
+
+// className resolves a numeric class to its mnemonic. Classes without a
+// mnemonic are rendered in the RFC 3597 `CLASSnnn` form.
+func className(code uint16) string {
+	return codeMnemonic(classCodes, "CLASS", code)
+}
+
+func mnemonicCode(codes map[string]uint16, prefix string, name string) (uint16, bool) {
+	name = strings.ToUpper(name)
+	if code, ok := codes[name]; ok == true {
+		return code, true
+	}
+	if strings.HasPrefix(name, prefix) == false {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(name[len(prefix):], 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(code), true
+}
+
+func codeMnemonic(codes map[string]uint16, prefix string, code uint16) string {
+	for name, c := range codes {
+		if c == code {
+			return name
+		}
+	}
+	return prefix + strconv.Itoa(int(code))
+}
@@ -0,0 +1,435 @@
+package zone
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Encoder writes zones in a specific format, e.g. zone file or CSV.
+type Encoder interface {
+	// Encode writes the records of a zone. Relative names are qualified with
+	// origin, which may be empty when the format and zone do not need one.
+	// Formats that always need one return [ErrNoOrigin] when it is empty.
+	Encode(w io.Writer, z *Zone, origin string) error
+
+	// Supports reports whether the format can represent records of the
+	// given type.
+	Supports(recordType string) bool
+}
+
+// Decoder reads zones from a specific format.
+type Decoder interface {
+	// Decode reads the records of a zone. Relative names are qualified with
+	// origin, which may be empty.
+	Decode(r io.Reader, origin string) (*Zone, error)
+
+	// Supports reports whether the format can represent records of the
+	// given type.
+	Supports(recordType string) bool
+}
+
+type formatEncoder struct {
+	encode   func(w io.Writer, z *Zone, origin string) error
+	supports func(recordType string) bool
+}
+
+func (e formatEncoder) Encode(w io.Writer, z *Zone, origin string) error {
+	return e.encode(w, z, origin)
+}
+
+func (e formatEncoder) Supports(recordType string) bool {
+	return e.supports == nil || e.supports(strings.ToUpper(recordType))
+}
+
+type formatDecoder struct {
+	decode   func(r io.Reader, origin string) (*Zone, error)
+	supports func(recordType string) bool
+}
+
+func (d formatDecoder) Decode(r io.Reader, origin string) (*Zone, error) {
+	return d.decode(r, origin)
+}
+
+func (d formatDecoder) Supports(recordType string) bool {
+	return d.supports == nil || d.supports(strings.ToUpper(recordType))
+}
+
+// NewEncoder creates an [Encoder] from an encode function. The supports
+// function receives record types in upper case. When it is nil, every
+// record type is supported.
+func NewEncoder(encode func(w io.Writer, z *Zone, origin string) error, supports func(recordType string) bool) Encoder {
+	return formatEncoder{encode: encode, supports: supports}
+}
+
+// NewDecoder creates a [Decoder] from a decode function. The supports
+// function receives record types in upper case. When it is nil, every
+// record type is supported.
+func NewDecoder(decode func(r io.Reader, origin string) (*Zone, error), supports func(recordType string) bool) Decoder {
+	return formatDecoder{decode: decode, supports: supports}
+}
+
+// formatRegistry holds the encoders and decoders that are available by
+// format name.
+type formatRegistry struct {
+	sync.RWMutex
+	encoders map[string]Encoder
+	decoders map[string]Decoder
+}
+
+func newRegistry() *formatRegistry {
+	return &formatRegistry{
+		encoders: make(map[string]Encoder),
+		decoders: make(map[string]Decoder),
+	}
+}
+
+// registry is the registry that the package level functions use.
+var registry = newRegistry()
+
+// RegisterEncoder makes an encoder available under a format name. Names are
+// case-insensitive, and a name can only be registered once.
+func RegisterEncoder(name string, encoder Encoder) error {
+	return registry.registerEncoder(name, encoder)
+}
+
+// RegisterDecoder makes a decoder available under a format name. Names are
+// case-insensitive, and a name can only be registered once.
+func RegisterDecoder(name string, decoder Decoder) error {
+	return registry.registerDecoder(name, decoder)
+}
+
+// LookupEncoder finds the encoder registered under a format name. The error
+// wraps [ErrUnknownFormat] when there is none.
+func LookupEncoder(name string) (Encoder, error) {
+	return registry.lookupEncoder(name)
+}
+
+// LookupDecoder finds the decoder registered under a format name. The error
+// wraps [ErrUnknownFormat] when there is none.
+func LookupDecoder(name string) (Decoder, error) {
+	return registry.lookupDecoder(name)
+}
+
+// EncoderNames lists the names of the registered encoders in sorted order.
+func EncoderNames() []string {
+	return registry.encoderNames()
+}
+
+// DecoderNames lists the names of the registered decoders in sorted order.
+func DecoderNames() []string {
+	return registry.decoderNames()
+}
+
+func (r *formatRegistry) registerEncoder(name string, encoder Encoder) error {
+	name = strings.ToLower(name)
+	if name == "" || encoder == nil {
+		return errors.New("an encoder requires a name and an implementation")
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.encoders[name]; ok == true {
+		return fmt.Errorf("encoder %q is already registered", name)
+	}
+	r.encoders[name] = encoder
+	return nil
+}
+
+func (r *formatRegistry) registerDecoder(name string, decoder Decoder) error {
+	name = strings.ToLower(name)
+	if name == "" || decoder == nil {
+		return errors.New("a decoder requires a name and an implementation")
+	}
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.decoders[name]; ok == true {
+		return fmt.Errorf("decoder %q is already registered", name)
+	}
+	r.decoders[name] = decoder
+	return nil
+}
+
+func (r *formatRegistry) lookupEncoder(name string) (Encoder, error) {
+	r.RLock()
+	defer r.RUnlock()
+	encoder, ok := r.encoders[strings.ToLower(name)]
+	if ok == false {
+		return nil, fmt.Errorf("encoder %q: %w", name, ErrUnknownFormat)
+	}
+	return encoder, nil
+}
+
+func (r *formatRegistry) lookupDecoder(name string) (Decoder, error) {
+	r.RLock()
+	defer r.RUnlock()
+	decoder, ok := r.decoders[strings.ToLower(name)]
+	if ok == false {
+		return nil, fmt.Errorf("decoder %q: %w", name, ErrUnknownFormat)
+	}
+	return decoder, nil
+}
+
+func (r *formatRegistry) encoderNames() []string {
+	r.RLock()
+	defer r.RUnlock()
+	result := make([]string, 0, len(r.encoders))
+	for name := range r.encoders {
+		result = append(result, name)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func (r *formatRegistry) decoderNames() []string {
+	r.RLock()
+	defer r.RUnlock()
+	result := make([]string, 0, len(r.decoders))
+	for name := range r.decoders {
+		result = append(result, name)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// UnsupportedTypes lists the record types of a zone, in upper case and
+// sorted order, that supports rejects. It is typically given the Supports
+// method of an [Encoder], to warn about records that would be lost.
+func UnsupportedTypes(z *Zone, supports func(recordType string) bool) []string {
+	records := z.AllRecords()
+
+	result := make([]string, 0)
+	for _, rr := range records {
+		recordType := strings.ToUpper(rr.Type)
+		if supports(recordType) == false && slices.Contains(result, recordType) == false {
+			result = append(result, recordType)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
+
+// qualifyZone returns a copy of a zone in which relative owner names, and
+// relative names within values, are qualified with origin. The zone is
+// returned unchanged when origin is empty.
+func qualifyZone(z *Zone, origin string) *Zone {
+	if origin == "" {
+		return z
+	}
+	origin = fqdn(origin)
+	qualify := func(rr ResourceRecord) ResourceRecord {
+		rr = qualifyRdata(rr, origin)
+		rr.Name = qualifyName(rr.Name, origin)
+		return rr
+	}
+
+	result := &Zone{
+		Records: make([]ResourceRecord, 0, len(z.Records)),
+	}
+	if z.SOA.IsEmpty() == false {
+		result.SOA = qualify(z.SOA)
+	}
+	for _, rr := range z.Records {
+		result.Records = append(result.Records, qualify(rr))
+	}
+	return result
+}
+
+// anyType is the supports function of formats that can represent records
+// of every type, e.g. in the generic form of RFC 3597.
+func anyType(recordType string) bool {
+	return true
+}
+
+// knownType is the supports function of formats that can represent records
+// of every type with a known type code.
+func knownType(recordType string) bool {
+	_, ok := recordTypeCode(recordType)
+	return ok
+}
+
+// requireOrigin wraps an encode function of a format that cannot work
+// without the origin of the zone, so that an empty origin is rejected
+// rather than taken to be the root.
+func requireOrigin(encode func(w io.Writer, z *Zone, origin string) error) func(w io.Writer, z *Zone, origin string) error {
+	return func(w io.Writer, z *Zone, origin string) error {
+		if origin == "" {
+			return ErrNoOrigin
+		}
+		return encode(w, z, origin)
+	}
+}
+
+// typeList creates a supports function that accepts the given types.
+func typeList(types ...string) func(recordType string) bool {
+	return func(recordType string) bool {
+		return slices.Contains(types, recordType)
+	}
+}
+
+// NewZoneFileDecoder creates a [Decoder] of zone files that are read with
+// the given parser options. Like the registered `bind` decoder, it
+// qualifies relative names within values with the origin that was in
+// effect on their line.
+func NewZoneFileDecoder(opts ...Option) Decoder {
+	return NewDecoder(parseZoneFile(opts...), nil)
+}
+
+// parseZoneFile creates a decode function that reads zone files with the
+// given parser options, and the origin given with [WithOrigin]. The parser
+// qualifies owner names, while relative names in values are qualified here
+// with the origin that was in effect on their line, as `$ORIGIN` directives
+// may change it.
+func parseZoneFile(opts ...Option) func(r io.Reader, origin string) (*Zone, error) {
+	return func(r io.Reader, origin string) (*Zone, error) {
+		parserOpts := append(slices.Clip(opts), WithProvenance(true))
+		if origin != "" {
+			parserOpts = append(parserOpts, WithOrigin(origin))
+		}
+		zp, err := NewZoneParser(parserOpts...)
+		if err != nil {
+			return nil, err
+		}
+		z, err := zp.Parse(r)
+		if err != nil {
+			return nil, err
+		}
+
+		qualify := func(rr ResourceRecord) ResourceRecord {
+			if rr.Provenance.Origin != "" {
+				rr = qualifyRdata(rr, fqdn(rr.Provenance.Origin))
+			}
+			rr.Provenance = nil
+			return rr
+		}
+		if z.SOA.IsEmpty() == false {
+			z.SOA = qualify(z.SOA)
+		}
+		for i, rr := range z.Records {
+			z.Records[i] = qualify(rr)
+		}
+		return z, nil
+	}
+}
+
+func encodeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// The formats provided by this package.
+func init() {
+	encoders := map[string]Encoder{
+		"bind": NewEncoder(func(w io.Writer, z *Zone, origin string) error {
+			if origin != "" {
+				_, err := io.WriteString(w, "$ORIGIN "+fqdn(origin)+"\n")
+				if err != nil {
+					return err
+				}
+			}
+			_, err := io.WriteString(w, z.String())
+			return err
+		}, anyType),
+		"compilezone": NewEncoder(requireOrigin(WriteCompileZone), knownType),
+		"csv": NewEncoder(func(w io.Writer, z *Zone, origin string) error {
+			if origin == "" {
+				return WriteCSV(w, z)
+			}
+			return WriteCSV(w, z, WithCSVOrigin(origin))
+		}, anyType),
+		"dnsmasq": NewEncoder(requireOrigin(WriteDnsmasq), typeList("A", "AAAA", "CNAME", "MX", "PTR", "SRV", "TXT")),
+		"hosts":   NewEncoder(WriteHosts, typeList("A", "AAAA", "CNAME")),
+		"json": NewEncoder(func(w io.Writer, z *Zone, origin string) error {
+			return encodeJSON(w, qualifyZone(z, origin))
+		}, anyType),
+		"livedns": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			sets, err := LiveDNSRecords(z, origin)
+			if err != nil {
+				return err
+			}
+			return encodeJSON(w, sets)
+		}), typeList(liveDNSTypes...)),
+		"nsupdate": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			return WriteNsupdate(w, Diff(nil, z), origin)
+		}), knownType),
+		"octodns": NewEncoder(requireOrigin(WriteOctoDNS), typeList(octoDNSTypes...)),
+		"powerdns": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			patch, err := PowerDNSZonePatch(z, origin)
+			if err != nil {
+				return err
+			}
+			return encodeJSON(w, patch)
+		}), typeList(powerDNSTypes...)),
+		"route53": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			batches, err := Route53ChangeBatches(Diff(nil, z), origin)
+			if err != nil {
+				return err
+			}
+			return encodeJSON(w, batches)
+		}), typeList(route53Types...)),
+		"terraform": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			return WriteTerraform(w, z, origin)
+		}), func(recordType string) bool { return recordType != "SOA" }),
+		"tinydns": NewEncoder(WriteTinyDNS, tinyDNSSupports),
+		"unbound": NewEncoder(requireOrigin(func(w io.Writer, z *Zone, origin string) error {
+			return WriteUnbound(w, z, origin)
+		}), knownType),
+	}
+	decoders := map[string]Decoder{
+		"bind": NewZoneFileDecoder(),
+		"csv": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			if origin == "" {
+				return ParseCSV(r)
+			}
+			return ParseCSV(r, WithCSVOrigin(origin))
+		}, nil),
+		"dig": NewDecoder(parseZoneFile(WithDigOutput(true)), nil),
+		"hosts": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			if origin == "" {
+				return ParseHosts(r)
+			}
+			return ParseHosts(r, WithHostsOrigin(origin))
+		}, typeList("A", "AAAA", "CNAME")),
+		"json": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			z := &Zone{}
+			err := json.NewDecoder(r).Decode(z)
+			if err != nil {
+				return nil, err
+			}
+			return qualifyZone(z, origin), nil
+		}, nil),
+		"livedns": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			z, err := ParseLiveDNS(r, origin)
+			if err != nil {
+				return nil, err
+			}
+			return qualifyZone(z, origin), nil
+		}, func(recordType string) bool { return recordType != "SOA" }),
+		"microsoft": NewDecoder(parseZoneFile(WithDialect(DialectMicrosoft)), nil),
+		"octodns": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			z, err := ParseOctoDNS(r, origin)
+			if err != nil {
+				return nil, err
+			}
+			return qualifyZone(z, origin), nil
+		}, typeList(octoDNSTypes...)),
+		"tinydns": NewDecoder(func(r io.Reader, origin string) (*Zone, error) {
+			z, err := ParseTinyDNS(r)
+			if err != nil {
+				return nil, err
+			}
+			return qualifyZone(z, origin), nil
+		}, nil),
+	}
+
+	for name, encoder := range encoders {
+		_ = RegisterEncoder(name, encoder)
+	}
+	for name, decoder := range decoders {
+		_ = RegisterDecoder(name, decoder)
+	}
+}
@@ -0,0 +1,153 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_Registry(t *testing.T) {
+	assert.Subset(t, EncoderNames(), []string{
+		"bind", "compilezone", "csv", "dnsmasq", "hosts", "json", "livedns", "nsupdate",
+		"octodns", "powerdns", "route53", "terraform", "tinydns", "unbound",
+	})
+	assert.Subset(t, DecoderNames(), []string{
+		"bind", "csv", "dig", "hosts", "json", "livedns", "microsoft", "octodns", "tinydns",
+	})
+
+	_, err := LookupEncoder("yaml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	_, err = LookupDecoder("yaml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	assert.ErrorContains(t, RegisterDecoder("bind", NewDecoder(nil, nil)), `decoder "bind" is already registered`)
+}
+
+func Test_formatRegistry(t *testing.T) {
+	r := newRegistry()
+	encoder := NewEncoder(func(w io.Writer, z *Zone, origin string) error {
+		_, err := io.WriteString(w, strings.ToUpper(z.String()))
+		return err
+	}, nil)
+	require.Nil(t, r.registerEncoder("Test-Upper", encoder))
+	assert.Equal(t, []string{"test-upper"}, r.encoderNames())
+	assert.Equal(t, []string{}, r.decoderNames())
+
+	found, err := r.lookupEncoder("test-upper")
+	require.Nil(t, err)
+	buf := bytes.Buffer{}
+	require.Nil(t, found.Encode(&buf, mustParse(t, "www 300 IN A 192.0.2.1\n"), ""))
+	assert.Equal(t, "WWW 300 IN A 192.0.2.1\n", buf.String())
+	assert.True(t, found.Supports("anything"))
+
+	_, err = LookupEncoder("test-upper")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	assert.ErrorContains(t, r.registerEncoder("TEST-UPPER", encoder), `encoder "test-upper" is already registered`)
+	assert.ErrorContains(t, r.registerEncoder("", encoder), "requires a name")
+	assert.ErrorContains(t, r.registerDecoder("bind", nil), "requires a name")
+}
+
+func Test_Registry_RoundTrip(t *testing.T) {
+	input := `@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 3600 IN NS ns1
+www 300 IN A 192.0.2.1
+@ 300 IN TXT "v=spf1 -all"
+`
+	bind, err := LookupDecoder("bind")
+	require.Nil(t, err)
+	z, err := bind.Decode(strings.NewReader(input), "example.com")
+	require.Nil(t, err)
+	expected := `example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+example.com. 3600 IN NS ns1.example.com.
+www.example.com. 300 IN A 192.0.2.1
+example.com. 300 IN TXT "v=spf1 -all"
+`
+	for _, name := range []string{"bind", "csv", "json"} {
+		encoder, err := LookupEncoder(name)
+		require.Nil(t, err)
+		decoder, err := LookupDecoder(name)
+		require.Nil(t, err)
+
+		buf := bytes.Buffer{}
+		require.Nil(t, encoder.Encode(&buf, z, "example.com."), name)
+		found, err := decoder.Decode(&buf, "example.com.")
+		require.Nil(t, err, name)
+		assert.Equal(t, expected, found.String(), name)
+	}
+}
+
+func Test_Registry_DecodeQualifies(t *testing.T) {
+	input := `@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 3600 IN MX 10 mail
+$ORIGIN sub.example.com.
+www 300 IN CNAME web
+`
+	for _, name := range []string{"bind", "microsoft"} {
+		decoder, err := LookupDecoder(name)
+		require.Nil(t, err)
+		z, err := decoder.Decode(strings.NewReader(input), "example.com")
+		require.Nil(t, err, name)
+		assert.Equal(t, `example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+example.com. 3600 IN MX 10 mail.example.com.
+www.sub.example.com. 300 IN CNAME web.sub.example.com.
+`, z.String(), name)
+		assert.Nil(t, z.Records[0].Provenance, name)
+	}
+}
+
+func Test_UnsupportedTypes(t *testing.T) {
+	z := mustParse(t, `@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+www 300 IN A 192.0.2.1
+www 300 IN aaaa 2001:db8::1
+@ 300 IN MX 10 mail
+@ 300 IN txt "hello"
+mail 300 IN MX 10 mail
+`)
+	hosts, err := LookupEncoder("hosts")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"MX", "SOA", "TXT"}, UnsupportedTypes(z, hosts.Supports))
+
+	octodns, err := LookupEncoder("octodns")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"SOA"}, UnsupportedTypes(z, octodns.Supports))
+	assert.True(t, octodns.Supports("aaaa"))
+
+	supports := map[string]map[string]bool{
+		"bind":     {"SOA": true, "TYPE65280": true},
+		"dnsmasq":  {"MX": true, "NS": false, "SOA": false},
+		"livedns":  {"ALIAS": true, "SOA": false, "NSEC": false},
+		"nsupdate": {"NSEC": true, "BOGUS": false},
+		"powerdns": {"SOA": true, "NSEC3": true, "NINFO": false},
+		"tinydns":  {"SOA": true, "NAPTR": true, "TYPE65280": true, "AXFR": false, "BOGUS": false},
+		"unbound":  {"SOA": true, "TYPE65280": true, "BOGUS": false},
+	}
+	for name, types := range supports {
+		encoder, err := LookupEncoder(name)
+		require.Nil(t, err)
+		for recordType, expected := range types {
+			assert.Equal(t, expected, encoder.Supports(recordType), name+" "+recordType)
+		}
+	}
+}
+
+func Test_Registry_RequireOrigin(t *testing.T) {
+	z := mustParse(t, "www 300 IN A 192.0.2.1\n")
+	for _, name := range []string{"compilezone", "dnsmasq", "livedns", "nsupdate", "octodns", "powerdns", "route53", "terraform", "unbound"} {
+		encoder, err := LookupEncoder(name)
+		require.Nil(t, err)
+		buf := bytes.Buffer{}
+		assert.ErrorIs(t, encoder.Encode(&buf, z, ""), ErrNoOrigin, name)
+		assert.Equal(t, "", buf.String(), name)
+		assert.Nil(t, encoder.Encode(&buf, z, "example.com."), name)
+	}
+
+	// Formats that do not need an origin write names as given.
+	z = mustParse(t, "www.example.com. 300 IN A 192.0.2.1\n")
+	for _, name := range []string{"bind", "csv", "hosts", "json", "tinydns"} {
+		encoder, err := LookupEncoder(name)
+		require.Nil(t, err)
+		assert.Nil(t, encoder.Encode(&bytes.Buffer{}, z, ""), name)
+	}
+}
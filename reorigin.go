@@ -0,0 +1,119 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reorigin moves the zone from one apex to another, e.g. when a domain is
+// renamed from `example.com.` to `example.net.`. Absolute owner names, and
+// absolute names in the values of records, that are at or below from are
+// rewritten to lie at or below to. Names outside from, and relative names,
+// are not changed. The values that are names are found with the RDATA
+// format of each type, e.g. the targets of NS, CNAME, MX, SRV, PTR, DNAME,
+// and SVCB records, the MNAME and RNAME of the SOA record, and the
+// replacement of NAPTR records.
+//
+// Values that cannot be rewritten safely are left unchanged, and reported
+// as [RecordError] values that wrap [ErrUnsafeRewrite] in the returned
+// error. These are values other than names that mention from, e.g. TXT
+// text or CAA values, values in the RFC 3597 generic form, and RRSIG and
+// NSEC3 records, which must be generated again. The zone is still moved
+// when such values are reported. An error that does not wrap
+// [ErrUnsafeRewrite] means that the zone was not changed.
+func (z *Zone) Reorigin(from string, to string) error {
+	if from == "" || to == "" {
+		return errors.New("origin must not be empty")
+	}
+	from, to = fqdn(from), fqdn(to)
+	if from == "." {
+		return errors.New("cannot move a zone from the root")
+	}
+
+	var errs []error
+	move := func(rr ResourceRecord) ResourceRecord {
+		rr, err := reoriginRecord(rr, from, to)
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+		}
+		return rr
+	}
+	if z.SOA.IsEmpty() == false {
+		z.SOA = move(z.SOA)
+	}
+	for i, rr := range z.Records {
+		z.Records[i] = move(rr)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// reoriginRecord returns a copy of a record that has been moved from one
+// apex to another. An error that wraps [ErrUnsafeRewrite] describes what
+// could not be moved.
+func reoriginRecord(rr ResourceRecord, from string, to string) (ResourceRecord, error) {
+	rr = cloneRecord(rr)
+	rr.Name = reoriginName(rr.Name, from, to)
+
+	recordType := strings.ToUpper(rr.Type)
+	if recordType == "RRSIG" || recordType == "NSEC3" {
+		if isInZone(rr.Name, to) {
+			return rr, fmt.Errorf("%s records must be generated again: %w", recordType, ErrUnsafeRewrite)
+		}
+		return rr, nil
+	}
+
+	schema, ok := rdataSchema(recordType)
+	if isGenericRdata(rr.Values) {
+		if ok == true && hasNameField(schema) {
+			return rr, fmt.Errorf("values in the generic form cannot be rewritten: %w", ErrUnsafeRewrite)
+		}
+		return rr, nil
+	}
+
+	mention := strings.ToLower(strings.TrimSuffix(from, "."))
+	mentioned := make([]string, 0)
+	for i, v := range rr.Values {
+		field, ok := rdataFieldAt(schema, i)
+		if ok == true && field.kind.isName() {
+			rr.Values[i] = reoriginName(v, from, to)
+			continue
+		}
+		if strings.Contains(strings.ToLower(v), mention) {
+			mentioned = append(mentioned, v)
+		}
+	}
+	if len(mentioned) > 0 {
+		return rr, fmt.Errorf("values mention %s: %s: %w", from, strings.Join(mentioned, " "), ErrUnsafeRewrite)
+	}
+	return rr, nil
+}
+
+// reoriginName rewrites an absolute name at or below from to the same name
+// at or below to. The case of the labels below from is kept.
+func reoriginName(name string, from string, to string) string {
+	if isAbsoluteName(name) == false || isInZone(name, from) == false {
+		return name
+	}
+	prefix := name[:len(name)-len(from)]
+	if prefix == "" {
+		return to
+	}
+	if to == "." {
+		return prefix
+	}
+	return prefix + to
+}
+
+// hasNameField determines if the RDATA of a type holds a domain name.
+func hasNameField(schema []rdataField) bool {
+	for _, field := range schema {
+		if field.kind.isName() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const reoriginZone = `$ORIGIN example.com.
+@ 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300
+@ 3600 IN NS ns1.example.com.
+@ 3600 IN NS ns.provider.net.
+@ 300 IN MX 10 Mail.Example.COM.
+www 300 IN CNAME example.com.
+_sip._tcp 300 IN SRV 10 5 5060 sip.example.com.
+old 300 IN DNAME legacy.example.com.
+svc 300 IN SVCB 1 svc.example.com. alpn=h2
+@ 300 IN NAPTR 100 10 "S" "SIP+D2U" "" _sip._udp.example.com.
+alias 300 IN CNAME www.example.org.
+relative 300 IN CNAME www
+@ 300 IN TXT "v=spf1 include:_spf.example.com -all"
+@ 300 IN CAA 0 iodef "mailto:security@example.com"
+@ 300 IN TXT "google-site-verification=abc"
+@ 300 IN RRSIG A 8 2 300 20240101000000 20230101000000 1 example.com. AAAA
+generic 300 IN CNAME \# 3 016100
+`
+
+func Test_Zone_Reorigin(t *testing.T) {
+	z := mustParse(t, reoriginZone)
+	err := z.Reorigin("EXAMPLE.com", "example.net.")
+	require.NotNil(t, err)
+	assert.ErrorIs(t, err, ErrUnsafeRewrite)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, `13: example.net. TXT: values mention EXAMPLE.com.: "v=spf1 include:_spf.example.com -all": value cannot be rewritten safely`, lines[0])
+	assert.Equal(t, `14: example.net. CAA: values mention EXAMPLE.com.: "mailto:security@example.com": value cannot be rewritten safely`, lines[1])
+	assert.Equal(t, `16: example.net. RRSIG: RRSIG records must be generated again: value cannot be rewritten safely`, lines[2])
+	assert.Equal(t, `17: generic.example.net. CNAME: values in the generic form cannot be rewritten: value cannot be rewritten safely`, lines[3])
+
+	assert.Equal(t, `example.net. 3600 IN SOA ns1.example.net. hostmaster.example.net. 1 7200 3600 1209600 300
+example.net. 3600 IN NS ns1.example.net.
+example.net. 3600 IN NS ns.provider.net.
+example.net. 300 IN MX 10 Mail.example.net.
+www.example.net. 300 IN CNAME example.net.
+_sip._tcp.example.net. 300 IN SRV 10 5 5060 sip.example.net.
+old.example.net. 300 IN DNAME legacy.example.net.
+svc.example.net. 300 IN SVCB 1 svc.example.net. alpn=h2
+example.net. 300 IN NAPTR 100 10 "S" "SIP+D2U" "" _sip._udp.example.net.
+alias.example.net. 300 IN CNAME www.example.org.
+relative.example.net. 300 IN CNAME www
+example.net. 300 IN TXT "v=spf1 include:_spf.example.com -all"
+example.net. 300 IN CAA 0 iodef "mailto:security@example.com"
+example.net. 300 IN TXT "google-site-verification=abc"
+example.net. 300 IN RRSIG A 8 2 300 20240101000000 20230101000000 1 example.com. AAAA
+generic.example.net. 300 IN CNAME \# 3 016100
+`, z.String())
+}
+
+func Test_Zone_Reorigin_Errors(t *testing.T) {
+	z := mustParse(t, "www.example.com. 300 IN A 192.0.2.1\n")
+	assert.ErrorContains(t, z.Reorigin("", "example.net."), "origin must not be empty")
+	assert.ErrorContains(t, z.Reorigin(".", "example.net."), "cannot move a zone from the root")
+	assert.Equal(t, "www.example.com.", z.Records[0].Name)
+
+	require.Nil(t, z.Reorigin("example.com.", "sub.example.org."))
+	assert.Equal(t, "www.sub.example.org.", z.Records[0].Name)
+	assert.Equal(t, "fooexample.com.", reoriginName("fooexample.com.", "example.com.", "example.net."))
+}
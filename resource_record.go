@@ -1,7 +1,10 @@
 package zone
 
 import (
+	"errors"
+	"fmt"
 	"github.com/spf13/cast"
+	"math"
 	"strings"
 )
 
@@ -11,6 +14,28 @@ type ResourceRecord struct {
 	Type   string
 	TTL    int
 	Values []string
+
+	// HasTTL indicates that TTL holds an explicit value. It distinguishes a
+	// TTL of `0` from a TTL that was not provided. A non-zero TTL is always
+	// considered to be present.
+	HasTTL bool
+
+	// Position is the location in the source the record was parsed from.
+	Position Position
+
+	// Provenance describes how the parser derived the Name, TTL, and Class.
+	// It is nil unless the parser was created with [WithProvenance].
+	Provenance *Provenance
+
+	// Metadata holds attributes of the record that are specific to the
+	// format it was read from and that zone files cannot express. Keys are
+	// prefixed with the name of the format, e.g. [MetadataTinyDNSLocation].
+	Metadata map[string]string
+
+	// Comments holds the comments on the lines of the record, and on the
+	// comment lines directly before it, without their leading `;`. It is
+	// nil unless the parser was created with [WithComments].
+	Comments []string
 }
 
 func (rr *ResourceRecord) String() string {
@@ -18,7 +43,7 @@ func (rr *ResourceRecord) String() string {
 	if rr.Name != "" {
 		str.WriteString(rr.Name + " ")
 	}
-	if rr.TTL > 0 {
+	if rr.HasTTL == true || rr.TTL > 0 {
 		str.WriteString(cast.ToString(rr.TTL) + " ")
 	}
 	if rr.Class != "" {
@@ -35,8 +60,52 @@ func (rr *ResourceRecord) String() string {
 
 func (rr *ResourceRecord) IsEmpty() bool {
 	return rr.TTL == 0 &&
+		rr.HasTTL == false &&
 		rr.Class == "" &&
 		rr.Name == "" &&
 		rr.Type == "" &&
 		len(rr.Values) == 0
 }
+
+// Validate checks that a record is complete and well-formed: the owner name
+// is a valid domain name, the TTL fits in 31 bits as required by RFC 2181
+// §8, the class and type are known, and the values can be encoded for the
+// record types whose format is understood. The returned error wraps
+// [ErrInvalidRdata].
+//
+// [ZoneParser.Parse] remains loose, and does not reject records that fail
+// validation.
+func (rr *ResourceRecord) Validate() error {
+	if rr.Name == "" {
+		return fmt.Errorf("missing owner name: %w", ErrInvalidRdata)
+	}
+	if rr.Name != "@" {
+		if _, err := packName(nil, fqdn(rr.Name)); err != nil {
+			return fmt.Errorf("invalid owner name: %s: %w", err, ErrInvalidRdata)
+		}
+	}
+	if rr.TTL < 0 || rr.TTL > math.MaxInt32 {
+		return fmt.Errorf("ttl %d is out of range: %w", rr.TTL, ErrInvalidRdata)
+	}
+	if rr.Class != "" && isClassToken.MatchString(rr.Class) == false {
+		return fmt.Errorf("unknown class %s: %w", rr.Class, ErrInvalidRdata)
+	}
+	if _, ok := recordTypeCode(rr.Type); ok == false && isRecordType([]byte(rr.Type)) == false {
+		return fmt.Errorf("unknown type %s: %w", rr.Type, ErrInvalidRdata)
+	}
+	if len(rr.Values) == 0 && isTextType(rr.Type) == false {
+		return fmt.Errorf("missing values: %w", ErrInvalidRdata)
+	}
+
+	// Names within the values only need to be well-formed, so relative
+	// names are checked as if they were absolute.
+	record := qualifyRdata(*rr, ".")
+	_, err := packRdata(record)
+	if err != nil && errors.Is(err, ErrUnsupportedRecord) == false {
+		if errors.Is(err, ErrInvalidRdata) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", err, ErrInvalidRdata)
+	}
+	return nil
+}
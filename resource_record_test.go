@@ -0,0 +1,35 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Validate(t *testing.T) {
+	valid := []ResourceRecord{
+		{Name: "www.example.com.", TTL: 300, Class: "IN", Type: "A", Values: []string{"192.0.2.1"}},
+		{Name: "@", Class: "in", Type: "mx", Values: []string{"10", "mail"}},
+		{Name: "example.com.", Type: "TXT"},
+		{Name: "example.com.", Type: "TYPE65280", Values: []string{`\#`, "2", "ABCD"}},
+		{Name: "example.com.", Type: "NSEC", Values: []string{"a.example.com.", "A", "RRSIG"}},
+	}
+	for _, rr := range valid {
+		assert.Nil(t, rr.Validate(), rr.String())
+	}
+
+	invalid := map[string]ResourceRecord{
+		"missing owner name":     {Type: "A", Values: []string{"192.0.2.1"}},
+		"invalid owner name":     {Name: "a..example.com.", Type: "A", Values: []string{"192.0.2.1"}},
+		"ttl -1 is out of range": {Name: "a", TTL: -1, Type: "A", Values: []string{"192.0.2.1"}},
+		"unknown class":          {Name: "a", Class: "XX", Type: "A", Values: []string{"192.0.2.1"}},
+		"unknown type":           {Name: "a", Type: "BOGUS", Values: []string{"1"}},
+		"missing values":         {Name: "a", Type: "NS"},
+		"not an address":         {Name: "a", Type: "AAAA", Values: []string{"192.0.2.1"}},
+		"not a 16 bit number":    {Name: "a", Type: "MX", Values: []string{"65536", "mail"}},
+	}
+	for message, rr := range invalid {
+		err := rr.Validate()
+		assert.ErrorIs(t, err, ErrInvalidRdata, message)
+		assert.ErrorContains(t, err, message)
+	}
+}
@@ -0,0 +1,231 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// route53MaxChanges is the maximum number of changes Route 53 accepts in a
+// single `ChangeResourceRecordSets` request.
+const route53MaxChanges = 1000
+
+// route53MaxRecords is the maximum number of `ResourceRecord` elements Route
+// 53 accepts in a single `ChangeResourceRecordSets` request. The elements of
+// an `UPSERT` change count twice.
+const route53MaxRecords = 1000
+
+// route53MaxCharacters is the maximum sum of the lengths of all `Value`
+// elements Route 53 accepts in a single `ChangeResourceRecordSets` request.
+// The values of an `UPSERT` change count twice.
+const route53MaxCharacters = 32000
+
+// route53Types lists the record types that Route 53 supports.
+var route53Types = []string{
+	"A", "AAAA", "CAA", "CNAME", "DS", "HTTPS", "MX", "NAPTR", "NS", "PTR",
+	"SOA", "SPF", "SRV", "SSHFP", "SVCB", "TLSA", "TXT",
+}
+
+// Route53ChangeBatch is the `ChangeBatch` of an AWS Route 53
+// [ChangeResourceRecordSets] request.
+//
+// [ChangeResourceRecordSets]: https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+type Route53ChangeBatch struct {
+	Comment string          `json:"Comment,omitempty"`
+	Changes []Route53Change `json:"Changes"`
+}
+
+type Route53Change struct {
+	// Action is one of `CREATE`, `UPSERT`, or `DELETE`.
+	Action            string                   `json:"Action"`
+	ResourceRecordSet Route53ResourceRecordSet `json:"ResourceRecordSet"`
+}
+
+type Route53ResourceRecordSet struct {
+	Name            string                  `json:"Name"`
+	Type            string                  `json:"Type"`
+	TTL             int                     `json:"TTL"`
+	ResourceRecords []Route53ResourceRecord `json:"ResourceRecords"`
+}
+
+type Route53ResourceRecord struct {
+	Value string `json:"Value"`
+}
+
+type Route53Option func(exporter *route53Exporter) error
+
+type route53Exporter struct {
+	apex        string
+	apexRecords bool
+	comment     string
+	maxChanges  int
+	upsertAdds  bool
+}
+
+// WithRoute53ApexRecords includes the SOA and NS record sets at the zone
+// apex when value is `true`. Route 53 creates these sets itself, so they are
+// skipped by default.
+func WithRoute53ApexRecords(value bool) Route53Option {
+	return func(exporter *route53Exporter) error {
+		exporter.apexRecords = value
+		return nil
+	}
+}
+
+// WithRoute53Comment sets the comment of every change batch.
+func WithRoute53Comment(comment string) Route53Option {
+	return func(exporter *route53Exporter) error {
+		exporter.comment = comment
+		return nil
+	}
+}
+
+// WithRoute53MaxChanges sets the maximum number of changes in each batch.
+// The default, and largest accepted value, is `1_000`.
+func WithRoute53MaxChanges(value int) Route53Option {
+	return func(exporter *route53Exporter) error {
+		if value < 1 || value > route53MaxChanges {
+			return fmt.Errorf("route 53 batches must have between 1 and %d changes: %d", route53MaxChanges, value)
+		}
+		exporter.maxChanges = value
+		return nil
+	}
+}
+
+// WithRoute53Upsert will use the `UPSERT` action, instead of `CREATE`, for
+// added record sets when value is `true`. This allows a whole zone, i.e.
+// `Diff(nil, z)`, to be applied to a hosted zone that already has some of
+// its records.
+func WithRoute53Upsert(value bool) Route53Option {
+	return func(exporter *route53Exporter) error {
+		exporter.upsertAdds = value
+		return nil
+	}
+}
+
+// Route53ChangeBatches converts record set changes, as produced by [Diff],
+// into Route 53 change batches for the hosted zone at apex. Added sets are
+// created, updated sets are upserted, and deleted sets are deleted.
+//
+// Owner names and domain names within record values are qualified with
+// apex. TXT strings longer than 255 bytes are split into multiple strings.
+// The changes are divided into batches of at most 1,000 changes, at most
+// 1,000 `ResourceRecord` elements, and at most 32,000 characters of values,
+// where the elements and values of an `UPSERT` change count twice. A change
+// that alone exceeds what a batch accepts is reported as an error.
+//
+// Records that Route 53 cannot represent, or that are outside apex, are
+// reported as [RecordError] values in the returned error.
+func Route53ChangeBatches(changes []RRSetChange, apex string, opts ...Route53Option) ([]Route53ChangeBatch, error) {
+	exporter := &route53Exporter{
+		apex:       fqdn(apex),
+		maxChanges: route53MaxChanges,
+	}
+	for _, opt := range opts {
+		err := opt(exporter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	result := make([]Route53ChangeBatch, 0)
+	batch := Route53ChangeBatch{Comment: exporter.comment}
+	elements, length := 0, 0
+	for _, change := range changes {
+		set, action := change.New, "CREATE"
+		switch change.Kind {
+		case ChangeAdd:
+			if exporter.upsertAdds == true {
+				action = "UPSERT"
+			}
+		case ChangeUpdate:
+			action = "UPSERT"
+		case ChangeDelete:
+			set, action = change.Old, "DELETE"
+		}
+		if exporter.skip(set) {
+			continue
+		}
+
+		recordSet, err := exporter.recordSet(set)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count, characters := len(recordSet.ResourceRecords), 0
+		for _, record := range recordSet.ResourceRecords {
+			characters += len(record.Value)
+		}
+		if action == "UPSERT" {
+			count, characters = count*2, characters*2
+		}
+		if count > route53MaxRecords {
+			err = fmt.Errorf("%d resource record elements exceed the %d accepted in a request: %w", count, route53MaxRecords, ErrUnsupportedRecord)
+			errs = append(errs, &RecordError{Record: set.Records[0], Err: err})
+			continue
+		}
+		if characters > route53MaxCharacters {
+			err = fmt.Errorf("%d characters of values exceed the %d accepted in a request: %w", characters, route53MaxCharacters, ErrUnsupportedRecord)
+			errs = append(errs, &RecordError{Record: set.Records[0], Err: err})
+			continue
+		}
+		if len(batch.Changes) == exporter.maxChanges || elements+count > route53MaxRecords || length+characters > route53MaxCharacters {
+			result = append(result, batch)
+			batch = Route53ChangeBatch{Comment: exporter.comment}
+			elements, length = 0, 0
+		}
+		batch.Changes = append(batch.Changes, Route53Change{Action: action, ResourceRecordSet: recordSet})
+		elements += count
+		length += characters
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(batch.Changes) > 0 {
+		result = append(result, batch)
+	}
+	return result, nil
+}
+
+// skip determines if a record set is one of the apex sets that Route 53
+// manages itself.
+func (exporter *route53Exporter) skip(set RRSet) bool {
+	if exporter.apexRecords == true {
+		return false
+	}
+	recordType := strings.ToUpper(set.Type)
+	if recordType != "SOA" && recordType != "NS" {
+		return false
+	}
+	return strings.EqualFold(qualifyName(set.Name, exporter.apex), exporter.apex)
+}
+
+func (exporter *route53Exporter) recordSet(set RRSet) (Route53ResourceRecordSet, error) {
+	result := Route53ResourceRecordSet{
+		Name:            qualifyName(set.Name, exporter.apex),
+		Type:            strings.ToUpper(set.Type),
+		TTL:             set.TTL,
+		ResourceRecords: make([]Route53ResourceRecord, 0, len(set.Records)),
+	}
+
+	var errs []error
+	for _, rr := range set.Records {
+		var err error
+		var value string
+		if isInZone(result.Name, exporter.apex) == false {
+			err = ErrOutOfZone
+		} else if slices.Contains(route53Types, result.Type) == false || strings.EqualFold(rr.Class, defaultClass) == false {
+			err = ErrUnsupportedRecord
+		} else {
+			value, err = formatRdata(qualifyRdata(rr, exporter.apex))
+		}
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+		result.ResourceRecords = append(result.ResourceRecords, Route53ResourceRecord{Value: value})
+	}
+	return result, errors.Join(errs...)
+}
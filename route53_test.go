@@ -0,0 +1,180 @@
+package zone
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_Route53ChangeBatches(t *testing.T) {
+	fixtures, err := readFixtures("testdata")
+	require.Nil(t, err)
+	defer closeFixtures(fixtures)
+
+	zp, _ := NewZoneParser()
+	z, err := zp.Parse(fixtures["simple.txt"].input)
+	require.Nil(t, err)
+
+	found, err := Route53ChangeBatches(Diff(nil, z), "example.com", WithRoute53Comment("initial import"))
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "initial import", found[0].Comment)
+
+	// The apex SOA and NS sets are skipped.
+	changes := found[0].Changes
+	require.Len(t, changes, 9)
+	assert.Equal(t, Route53Change{
+		Action: "CREATE",
+		ResourceRecordSet: Route53ResourceRecordSet{
+			Name: "example.com.",
+			Type: "MX",
+			TTL:  86400,
+			ResourceRecords: []Route53ResourceRecord{
+				{Value: "10 mail.example.com."},
+				{Value: "20 mail2.example.com."},
+			},
+		},
+	}, changes[0])
+
+	// Relative targets are qualified.
+	data, err := json.Marshal(changes[8])
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"Action": "CREATE",
+		"ResourceRecordSet": {
+			"Name": "www.example.com.",
+			"Type": "CNAME",
+			"TTL": 86400,
+			"ResourceRecords": [{"Value": "server1.example.com."}]
+		}
+	}`, string(data))
+
+	found, err = Route53ChangeBatches(Diff(nil, z), "example.com.", WithRoute53ApexRecords(true), WithRoute53Upsert(true))
+	require.Nil(t, err)
+	require.Len(t, found[0].Changes, 11)
+	assert.Equal(t, "SOA", found[0].Changes[0].ResourceRecordSet.Type)
+	assert.Equal(t, "UPSERT", found[0].Changes[0].Action)
+}
+
+func Test_Route53ChangeBatches_Diff(t *testing.T) {
+	old := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.1
+ftp 300 IN A 192.0.2.3
+`)
+	new := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.2
+api 300 IN CNAME www
+`)
+
+	found, err := Route53ChangeBatches(Diff(old, new), "example.com.", WithRoute53MaxChanges(2))
+	require.Nil(t, err)
+	require.Len(t, found, 2)
+	require.Len(t, found[0].Changes, 2)
+	assert.Equal(t, "DELETE", found[0].Changes[0].Action)
+	assert.Equal(t, "ftp.example.com.", found[0].Changes[0].ResourceRecordSet.Name)
+	assert.Equal(t, "192.0.2.3", found[0].Changes[0].ResourceRecordSet.ResourceRecords[0].Value)
+	assert.Equal(t, "UPSERT", found[0].Changes[1].Action)
+	assert.Equal(t, "CREATE", found[1].Changes[0].Action)
+	assert.Equal(t, "www.example.com.", found[1].Changes[0].ResourceRecordSet.ResourceRecords[0].Value)
+
+	_, err = Route53ChangeBatches(nil, "example.com.", WithRoute53MaxChanges(1001))
+	assert.ErrorContains(t, err, "between 1 and 1000 changes")
+}
+
+func Test_Route53ChangeBatches_Values(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	z := mustParse(t, strings.Join([]string{
+		"$ORIGIN example.com.",
+		"@ 300 IN TXT \"" + long + "\" \"v=spf1 -all\"",
+		"@ 300 IN CAA 0 issue letsencrypt.org",
+		"",
+	}, "\n"))
+
+	found, err := Route53ChangeBatches(Diff(nil, z), "example.com.")
+	require.Nil(t, err)
+	changes := found[0].Changes
+	assert.Equal(
+		t,
+		`"`+strings.Repeat("a", 255)+`" "`+strings.Repeat("a", 45)+`" "v=spf1 -all"`,
+		changes[0].ResourceRecordSet.ResourceRecords[0].Value,
+	)
+	assert.Equal(t, `0 issue "letsencrypt.org"`, changes[1].ResourceRecordSet.ResourceRecords[0].Value)
+}
+
+func Test_Route53ChangeBatches_Unsupported(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+www 300 IN A 192.0.2.1
+@ 300 IN LOC 52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m
+other.example.net. 300 IN A 192.0.2.1
+`)
+
+	found, err := Route53ChangeBatches(Diff(nil, z), "example.com.")
+	assert.Nil(t, found)
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.ErrorContains(t, err, "3: example.com. LOC")
+}
+
+func Test_Route53ChangeBatches_Elements(t *testing.T) {
+	lines := []string{"$ORIGIN example.com."}
+	for i := 0; i < 400; i++ {
+		lines = append(lines, fmt.Sprintf("one 300 IN AAAA 2001:db8::%x", i))
+		lines = append(lines, fmt.Sprintf("two 300 IN AAAA 2001:db8::%x", i))
+	}
+	z := mustParse(t, strings.Join(lines, "\n")+"\n")
+
+	// Both sets fit in one batch when they are created.
+	found, err := Route53ChangeBatches(Diff(nil, z), "example.com.")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Len(t, found[0].Changes, 2)
+
+	// The elements of an UPSERT change count twice.
+	found, err = Route53ChangeBatches(Diff(nil, z), "example.com.", WithRoute53Upsert(true))
+	require.Nil(t, err)
+	require.Len(t, found, 2)
+	assert.Len(t, found[0].Changes, 1)
+	assert.Equal(t, "one.example.com.", found[0].Changes[0].ResourceRecordSet.Name)
+	assert.Len(t, found[1].Changes, 1)
+
+	for i := 400; i < 501; i++ {
+		require.Nil(t, z.Add(ResourceRecord{Name: "one.example.com.", Class: "IN", Type: "AAAA", TTL: 300, Values: []string{fmt.Sprintf("2001:db8::%x", i)}}))
+	}
+	_, err = Route53ChangeBatches(Diff(nil, z), "example.com.", WithRoute53Upsert(true))
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.ErrorContains(t, err, "1002 resource record elements exceed the 1000 accepted in a request")
+}
+
+func Test_Route53ChangeBatches_Characters(t *testing.T) {
+	value := strings.Repeat("a", 250)
+	lines := []string{"$ORIGIN example.com."}
+	for i := 0; i < 40; i++ {
+		lines = append(lines, fmt.Sprintf("one 300 IN TXT \"%d%s\"", i, value))
+		lines = append(lines, fmt.Sprintf("two 300 IN TXT \"%d%s\"", i, value))
+	}
+	z := mustParse(t, strings.Join(lines, "\n")+"\n")
+
+	// Both sets, of about 10,000 characters each, fit in one batch when
+	// they are created.
+	found, err := Route53ChangeBatches(Diff(nil, z), "example.com.")
+	require.Nil(t, err)
+	require.Len(t, found, 1)
+	assert.Len(t, found[0].Changes, 2)
+
+	// The values of an UPSERT change count twice.
+	found, err = Route53ChangeBatches(Diff(nil, z), "example.com.", WithRoute53Upsert(true))
+	require.Nil(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "one.example.com.", found[0].Changes[0].ResourceRecordSet.Name)
+	assert.Equal(t, "two.example.com.", found[1].Changes[0].ResourceRecordSet.Name)
+
+	for i := 40; i < 70; i++ {
+		require.Nil(t, z.Add(ResourceRecord{Name: "one.example.com.", Class: "IN", Type: "TXT", TTL: 300, Values: []string{fmt.Sprintf("\"%d%s\"", i, value)}}))
+	}
+	_, err = Route53ChangeBatches(Diff(nil, z), "example.com.", WithRoute53Upsert(true))
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.ErrorContains(t, err, "characters of values exceed the 32000 accepted in a request")
+}
@@ -0,0 +1,58 @@
+package zone
+
+import "strings"
+
+// RRSet is a set of resource records that share an owner name, class, and
+// type, as described in RFC 2181 §5.
+type RRSet struct {
+	Name  string
+	Class string
+	Type  string
+
+	// TTL is the lowest TTL among the records of the set. RFC 2181 §5.2
+	// requires that all records of a set have the same TTL, and that a set
+	// with differing TTLs be treated as having the lowest of them.
+	TTL int
+
+	Records []ResourceRecord
+}
+
+// RRSets groups the SOA record and other records of the zone into record
+// sets. Sets are ordered by the first appearance of their owner name, class,
+// and type. Names, classes, and types are compared case-insensitively.
+func (z *Zone) RRSets() []RRSet {
+	return groupRRSets(z.AllRecords())
+}
+
+// groupRRSets groups a list of records into record sets, preserving the order
+// in which each set first appears.
+func groupRRSets(records []ResourceRecord) []RRSet {
+	result := make([]RRSet, 0)
+	index := make(map[string]int)
+	for _, rr := range records {
+		key := rrsetKey(rr)
+		i, ok := index[key]
+		if ok == false {
+			index[key] = len(result)
+			result = append(result, RRSet{
+				Name:    rr.Name,
+				Class:   rr.Class,
+				Type:    rr.Type,
+				TTL:     rr.TTL,
+				Records: []ResourceRecord{rr},
+			})
+			continue
+		}
+
+		set := &result[i]
+		set.Records = append(set.Records, rr)
+		if rr.TTL < set.TTL {
+			set.TTL = rr.TTL
+		}
+	}
+	return result
+}
+
+func rrsetKey(rr ResourceRecord) string {
+	return strings.ToLower(rr.Name) + " " + strings.ToUpper(rr.Class) + " " + strings.ToUpper(rr.Type)
+}
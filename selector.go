@@ -0,0 +1,355 @@
+package zone
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Selector is a compiled expression that filters records, see
+// [ParseSelector].
+type Selector struct {
+	expr string
+	root selectorNode
+}
+
+// selectorTerm matches a comparison, e.g. `ttl<300`.
+var selectorTerm = regexp.MustCompile(`^([a-z_]+)(!=|!~|<=|>=|\$=|=|~|<|>)(.*)$`)
+
+// ParseSelector compiles a selector expression. An expression is a list of
+// comparisons of the form `field operator value`, written without spaces,
+// that must all hold, e.g.:
+//
+//	type=A,AAAA name=*.dev.example.com. ttl<300 value~^10\.
+//
+// The fields are `name`, `type`, `class`, `ttl`, `value` for all values
+// joined by spaces, and the fields of the RDATA of a record, as described
+// for [PolicyAssertion.Field]. Other fields are reported as errors. The
+// operators are:
+//
+//   - `=` and `!=` compare with a comma separated list of values, of which
+//     one must, or none may, be equal. Values may be glob patterns, in
+//     which `*` matches any characters, including dots, and `?` matches one
+//     character. Names, types, and classes are compared case-insensitively,
+//     and other values case-sensitively.
+//   - `$=` matches names that equal, or are below, a domain, e.g.
+//     `name$=example.com.`.
+//   - `~` and `!~` match a regular expression.
+//   - `<`, `<=`, `>`, and `>=` compare numbers.
+//
+// Comparisons are combined with `and`, which is implied between adjacent
+// comparisons, `or`, and `not`, and grouped with parentheses. Values that
+// hold spaces or parentheses are quoted, e.g. `value~"v=spf1 .*"`. An empty
+// expression matches every record.
+func ParseSelector(expr string) (*Selector, error) {
+	tokens, err := tokenizeSelector(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parse selector: %w", err)
+	}
+	p := &selectorParser{tokens: tokens}
+	var root selectorNode = selectorAll{}
+	if len(tokens) > 0 {
+		root, err = p.parseOr()
+		if err == nil && p.pos < len(tokens) {
+			err = fmt.Errorf("unexpected %q", tokens[p.pos])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse selector: %w", err)
+		}
+	}
+	return &Selector{expr: expr, root: root}, nil
+}
+
+// String returns the expression the selector was compiled from.
+func (s *Selector) String() string {
+	return s.expr
+}
+
+// Matches determines if a record is selected.
+func (s *Selector) Matches(rr ResourceRecord) bool {
+	return s.root.matches(rr)
+}
+
+// Select creates a zone of the records that a selector expression matches,
+// see [ParseSelector]. The SOA record is kept when it matches. The zone is
+// not changed.
+func (z *Zone) Select(expr string) (*Zone, error) {
+	selector, err := ParseSelector(expr)
+	if err != nil {
+		return nil, err
+	}
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	if z.SOA.IsEmpty() == false && selector.Matches(z.SOA) {
+		result.SOA = z.SOA
+	}
+	for _, rr := range z.Records {
+		if selector.Matches(rr) {
+			result.Records = append(result.Records, rr)
+		}
+	}
+	return result, nil
+}
+
+// tokenizeSelector splits an expression into comparisons, keywords, and
+// parentheses. Quotes group characters into a token, and are kept.
+func tokenizeSelector(expr string) ([]string, error) {
+	result := make([]string, 0)
+	token := strings.Builder{}
+	inQuote := false
+	flush := func() {
+		if token.Len() > 0 {
+			result = append(result, token.String())
+			token.Reset()
+		}
+	}
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case inQuote && c == escapeByte && i+1 < len(expr):
+			token.WriteByte(c)
+			token.WriteByte(expr[i+1])
+			i += 1
+		case c == quoteByte:
+			inQuote = inQuote == false
+			token.WriteByte(c)
+		case inQuote:
+			token.WriteByte(c)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+		case c == bracketOpenByte || c == bracketCloseByte:
+			flush()
+			result = append(result, string(c))
+		default:
+			token.WriteByte(c)
+		}
+	}
+	if inQuote == true {
+		return nil, errors.New("unterminated quote")
+	}
+	flush()
+	return result, nil
+}
+
+type selectorParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *selectorParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *selectorParser) parseOr() (selectorNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.pos += 1
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = selectorOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseAnd() (selectorNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		next := p.peek()
+		if next == "" || next == ")" || strings.EqualFold(next, "or") {
+			return left, nil
+		}
+		if strings.EqualFold(next, "and") {
+			p.pos += 1
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = selectorAnd{left, right}
+	}
+}
+
+func (p *selectorParser) parseUnary() (selectorNode, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end of expression")
+	case strings.EqualFold(token, "not"):
+		p.pos += 1
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return selectorNot{node}, nil
+	case token == "(":
+		p.pos += 1
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing )")
+		}
+		p.pos += 1
+		return node, nil
+	}
+	p.pos += 1
+	return parseSelectorTerm(token)
+}
+
+// parseSelectorTerm compiles a comparison.
+func parseSelectorTerm(token string) (selectorNode, error) {
+	matches := selectorTerm.FindStringSubmatch(token)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid comparison %q", token)
+	}
+	term := &selectorCompare{field: matches[1], op: matches[2], value: matches[3]}
+	if isRecordField(term.field) == false {
+		return nil, fmt.Errorf("invalid comparison %q: unknown field %s", token, term.field)
+	}
+	if len(term.value) >= 2 && term.value[0] == quoteByte && term.value[len(term.value)-1] == quoteByte {
+		value, err := parseCharacterString(term.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comparison %q: %w", token, err)
+		}
+		term.value = value
+	}
+
+	switch term.op {
+	case "~", "!~":
+		re, err := regexp.Compile(term.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comparison %q: %w", token, err)
+		}
+		term.pattern = re
+	case "<", "<=", ">", ">=":
+		number, err := strconv.Atoi(term.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid comparison %q: %s is not a number", token, term.value)
+		}
+		term.number = number
+	case "=", "!=":
+		for _, v := range strings.Split(term.value, ",") {
+			term.list = append(term.list, v)
+			if strings.ContainsAny(v, "*?") {
+				term.globs = append(term.globs, globPattern(v, false))
+				term.foldGlobs = append(term.foldGlobs, globPattern(v, true))
+			}
+		}
+	}
+	return term, nil
+}
+
+// selectorNode is a node of a compiled selector expression.
+type selectorNode interface {
+	matches(rr ResourceRecord) bool
+}
+
+type selectorAll struct{}
+
+func (selectorAll) matches(ResourceRecord) bool { return true }
+
+type selectorAnd [2]selectorNode
+
+func (n selectorAnd) matches(rr ResourceRecord) bool { return n[0].matches(rr) && n[1].matches(rr) }
+
+type selectorOr [2]selectorNode
+
+func (n selectorOr) matches(rr ResourceRecord) bool { return n[0].matches(rr) || n[1].matches(rr) }
+
+type selectorNot [1]selectorNode
+
+func (n selectorNot) matches(rr ResourceRecord) bool { return n[0].matches(rr) == false }
+
+// selectorCompare compares a field of a record with a value.
+type selectorCompare struct {
+	field string
+	op    string
+	value string
+
+	list      []string
+	globs     []*regexp.Regexp
+	foldGlobs []*regexp.Regexp
+	pattern   *regexp.Regexp
+	number    int
+}
+
+func (n *selectorCompare) matches(rr ResourceRecord) bool {
+	value, isName, ok := policyField(rr, n.field)
+	if ok == false {
+		return false
+	}
+	fold := isName || n.field == "type" || n.field == "class"
+
+	switch n.op {
+	case "=":
+		return n.equals(value, isName, fold)
+	case "!=":
+		return n.equals(value, isName, fold) == false
+	case "$=":
+		return isInZone(fqdn(value), fqdn(n.value))
+	case "~":
+		return n.pattern.MatchString(value)
+	case "!~":
+		return n.pattern.MatchString(value) == false
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return number < n.number
+	case "<=":
+		return number <= n.number
+	case ">":
+		return number > n.number
+	case ">=":
+		return number >= n.number
+	}
+	return false
+}
+
+// equals determines if a value is one of the listed values, or matches one
+// of the glob patterns.
+func (n *selectorCompare) equals(value string, isName bool, fold bool) bool {
+	globs := n.globs
+	if fold == true {
+		globs = n.foldGlobs
+	}
+	for _, glob := range globs {
+		if glob.MatchString(value) {
+			return true
+		}
+	}
+	for _, v := range n.list {
+		switch {
+		case isName:
+			if strings.EqualFold(fqdn(v), fqdn(value)) {
+				return true
+			}
+		case fold:
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		case v == value:
+			return true
+		}
+	}
+	return false
+}
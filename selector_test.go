@@ -0,0 +1,82 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const selectorZone = `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 3600 IN NS ns1
+app.dev 60 IN A 10.0.0.1
+db.dev 300 IN AAAA 2001:db8::1
+WWW 300 IN A 192.0.2.1
+@ 300 IN MX 10 mail
+@ 300 IN TXT "v=spf1 -all"
+dev.example.net. 60 IN A 10.0.0.2
+`
+
+func Test_Zone_Select(t *testing.T) {
+	z := mustParse(t, selectorZone)
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: `type=A,AAAA name=*.dev.example.com. ttl<300 value~^10\.`, expected: []string{"app.dev.example.com."}},
+		{expr: `type=a,aaaa name=*.DEV.example.com.`, expected: []string{"app.dev.example.com.", "db.dev.example.com."}},
+		{expr: `name$=dev.example.com.`, expected: []string{"app.dev.example.com.", "db.dev.example.com."}},
+		{expr: `name$=example.com. and not type=SOA,NS,MX,TXT`, expected: []string{"app.dev.example.com.", "db.dev.example.com.", "WWW.example.com."}},
+		{expr: `name=www.example.com`, expected: []string{"WWW.example.com."}},
+		{expr: `ttl>=300 (type=MX or type=TXT)`, expected: []string{"example.com.", "example.com."}},
+		{expr: `type=A ttl=60 or exchange=mail`, expected: []string{"app.dev.example.com.", "example.com.", "dev.example.net."}},
+		{expr: `text~"spf1 -all"`, expected: []string{"example.com."}},
+		{expr: `type!=A,AAAA,NS,MX,TXT`, expected: []string{"example.com."}},
+		{expr: `class=in value!~^10\. ttl>300`, expected: []string{"example.com.", "example.com."}},
+		{expr: `not (name$=example.com.)`, expected: []string{"dev.example.net."}},
+		{expr: `text="v=spf1 *"`, expected: []string{"example.com."}},
+		{expr: `text="V=SPF1 *"`, expected: []string{}},
+		{expr: `exchange=MAI*`, expected: []string{"example.com."}},
+		{expr: ``, expected: []string{"example.com.", "example.com.", "app.dev.example.com.", "db.dev.example.com.", "WWW.example.com.", "example.com.", "example.com.", "dev.example.net."}},
+	}
+	for _, test := range tests {
+		found, err := z.Select(test.expr)
+		require.Nil(t, err, test.expr)
+		names := make([]string, 0)
+		if found.SOA.IsEmpty() == false {
+			names = append(names, found.SOA.Name)
+		}
+		for _, rr := range found.Records {
+			names = append(names, rr.Name)
+		}
+		assert.Equal(t, test.expected, names, test.expr)
+	}
+	assert.Len(t, z.Records, 7)
+}
+
+func Test_ParseSelector_Errors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{expr: `type`, expected: `invalid comparison "type"`},
+		{expr: `ttl<abc`, expected: "abc is not a number"},
+		{expr: `value~"("`, expected: "missing closing )"},
+		{expr: `value~"(`, expected: "unterminated quote"},
+		{expr: `(type=A`, expected: "missing )"},
+		{expr: `type=A )`, expected: `unexpected ")"`},
+		{expr: `type=A or`, expected: "unexpected end of expression"},
+		{expr: `not`, expected: "unexpected end of expression"},
+		{expr: `nmae=www`, expected: `invalid comparison "nmae=www": unknown field nmae`},
+		{expr: `not nmae=www`, expected: "unknown field nmae"},
+	}
+	for _, test := range tests {
+		_, err := ParseSelector(test.expr)
+		assert.ErrorContains(t, err, test.expected, test.expr)
+	}
+
+	selector, err := ParseSelector(`value~"a b" (ttl>1)`)
+	require.Nil(t, err)
+	assert.Equal(t, `value~"a b" (ttl>1)`, selector.String())
+	assert.True(t, selector.Matches(ResourceRecord{Type: "TXT", TTL: 2, Values: []string{`"a`, `b"`}}))
+}
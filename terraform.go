@@ -0,0 +1,249 @@
+package zone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TerraformSchema selects the shape of the resources written by
+// [WriteTerraform].
+type TerraformSchema int
+
+const (
+	// TerraformGeneric writes `dns_record` resources in the shape shared by
+	// providers with a single generic record resource:
+	//
+	//	resource "dns_record" "www_a" {
+	//	  zone    = "example.com."
+	//	  name    = "www"
+	//	  type    = "A"
+	//	  ttl     = 300
+	//	  records = ["192.0.2.1"]
+	//	}
+	TerraformGeneric TerraformSchema = iota
+
+	// TerraformRoute53 writes `aws_route53_record` resources for the AWS
+	// provider:
+	//
+	//	resource "aws_route53_record" "www_a" {
+	//	  zone_id = var.zone_id
+	//	  name    = "www.example.com."
+	//	  type    = "A"
+	//	  ttl     = 300
+	//	  records = ["192.0.2.1"]
+	//	}
+	TerraformRoute53
+)
+
+type TerraformOption func(generator *terraformGenerator) error
+
+type terraformGenerator struct {
+	apex   string
+	schema TerraformSchema
+	zoneID string
+}
+
+// WithTerraformSchema selects the resource shape. The default is
+// [TerraformGeneric].
+func WithTerraformSchema(schema TerraformSchema) TerraformOption {
+	return func(generator *terraformGenerator) error {
+		if schema != TerraformGeneric && schema != TerraformRoute53 {
+			return fmt.Errorf("unknown terraform schema: %d", schema)
+		}
+		generator.schema = schema
+		return nil
+	}
+}
+
+// WithTerraformZoneID sets the HCL expression that is written as the
+// `zone_id` of [TerraformRoute53] resources. The expression is written as
+// given, e.g. `aws_route53_zone.main.zone_id`. The default is `var.zone_id`.
+func WithTerraformZoneID(expression string) TerraformOption {
+	return func(generator *terraformGenerator) error {
+		if expression == "" {
+			return errors.New("terraform zone id expression must not be empty")
+		}
+		generator.zoneID = expression
+		return nil
+	}
+}
+
+// WriteTerraform writes one Terraform resource per record set of a zone for
+// the zone at apex. The SOA record is omitted because DNS providers manage
+// it.
+//
+// Resource names are derived from the owner name, relative to apex, and the
+// record type, e.g. `_sip._tcp` SRV records become `_sip__tcp_srv` and the
+// apex is written as `apex`. Names that would collide are numbered in the
+// order of the zone.
+//
+// Records of a class other than IN, or that are outside apex, are reported
+// as [RecordError] values in the returned error, and nothing is written.
+func WriteTerraform(w io.Writer, z *Zone, apex string, opts ...TerraformOption) error {
+	generator := &terraformGenerator{
+		apex:   fqdn(apex),
+		schema: TerraformGeneric,
+		zoneID: "var.zone_id",
+	}
+	for _, opt := range opts {
+		err := opt(generator)
+		if err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	records := make([]ResourceRecord, 0, len(z.Records))
+	for _, rr := range z.Records {
+		if strings.EqualFold(rr.Type, "SOA") {
+			continue
+		}
+		rr.Name = qualifyName(rr.Name, generator.apex)
+		if isInZone(rr.Name, generator.apex) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrOutOfZone})
+			continue
+		}
+		if strings.EqualFold(rr.Class, defaultClass) == false {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrUnsupportedRecord})
+			continue
+		}
+		records = append(records, rr)
+	}
+
+	buf := bytes.Buffer{}
+	names := make(map[string]int)
+	for _, set := range groupRRSets(records) {
+		values := make([]string, 0, len(set.Records))
+		for _, rr := range set.Records {
+			value, err := generator.value(qualifyRdata(rr, generator.apex))
+			if err != nil {
+				errs = append(errs, &RecordError{Record: rr, Err: err})
+				continue
+			}
+			values = append(values, hclString(value))
+		}
+
+		relative, _ := relativeName(set.Name, generator.apex)
+		resourceName := terraformResourceName(relative, set.Type)
+		names[resourceName] += 1
+		if names[resourceName] > 1 {
+			resourceName += "_" + strconv.Itoa(names[resourceName])
+		}
+
+		attributes := [][2]string{}
+		if generator.schema == TerraformRoute53 {
+			buf.WriteString(`resource "aws_route53_record" ` + hclString(resourceName) + " {\n")
+			attributes = append(attributes, [2]string{"zone_id", generator.zoneID}, [2]string{"name", hclString(set.Name)})
+		} else {
+			buf.WriteString(`resource "dns_record" ` + hclString(resourceName) + " {\n")
+			attributes = append(attributes, [2]string{"zone", hclString(generator.apex)}, [2]string{"name", hclString(relative)})
+		}
+		attributes = append(attributes,
+			[2]string{"type", hclString(strings.ToUpper(set.Type))},
+			[2]string{"ttl", strconv.Itoa(set.TTL)},
+			[2]string{"records", "[" + strings.Join(values, ", ") + "]"},
+		)
+		for _, attribute := range attributes {
+			buf.WriteString(fmt.Sprintf("  %-7s = %s\n", attribute[0], attribute[1]))
+		}
+		buf.WriteString("}\n\n")
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// value renders the values of a record as a resource's record value.
+//
+// The generic schema takes the text of TXT records as a single unquoted
+// string. Route 53 takes the strings of TXT records unquoted, with `""`
+// separating strings.
+func (generator *terraformGenerator) value(rr ResourceRecord) (string, error) {
+	if isTextType(rr.Type) == false {
+		return formatRdata(rr)
+	}
+
+	strs, err := parseCharacterStrings(rr.Values)
+	if err != nil {
+		return "", err
+	}
+	if generator.schema == TerraformGeneric {
+		text := strings.Join(strs, "")
+		if utf8.ValidString(text) == false {
+			return "", fmt.Errorf("text is not valid UTF-8: %w", ErrUnsupportedRecord)
+		}
+		return text, nil
+	}
+
+	chunks := make([]string, 0, len(strs))
+	for _, str := range strs {
+		for _, chunk := range splitCharacterString(str) {
+			quoted := quoteCharacterString(chunk)
+			chunks = append(chunks, quoted[1:len(quoted)-1])
+		}
+	}
+	return strings.Join(chunks, `""`), nil
+}
+
+// terraformResourceName derives a Terraform identifier from a relative
+// owner name and record type.
+func terraformResourceName(name string, recordType string) string {
+	if name == "@" {
+		name = "apex"
+	}
+	name = strings.ReplaceAll(name, "*", "wildcard")
+
+	str := strings.Builder{}
+	for _, r := range strings.ToLower(name + "_" + recordType) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			str.WriteRune(r)
+			continue
+		}
+		str.WriteByte('_')
+	}
+
+	// Identifiers must start with a letter or underscore.
+	result := str.String()
+	if (result[0] >= '0' && result[0] <= '9') || result[0] == '-' {
+		result = "_" + result
+	}
+	return result
+}
+
+// hclString renders a value as a quoted HCL string. Quotes, backslashes,
+// and control characters are escaped, as are the `${` and `%{` sequences
+// that would otherwise start a template.
+func hclString(value string) string {
+	str := strings.Builder{}
+	str.WriteByte('"')
+	for i, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			str.WriteByte('\\')
+			str.WriteRune(r)
+		case r == '\n':
+			str.WriteString(`\n`)
+		case r == '\r':
+			str.WriteString(`\r`)
+		case r == '\t':
+			str.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			str.WriteString(fmt.Sprintf(`\u%04x`, r))
+		case (r == '$' || r == '%') && strings.HasPrefix(value[i+1:], "{"):
+			str.WriteRune(r)
+			str.WriteRune(r)
+		default:
+			str.WriteRune(r)
+		}
+	}
+	str.WriteByte('"')
+	return str.String()
+}
@@ -0,0 +1,145 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const terraformSample = `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 300 IN MX 10 mail
+@ 300 IN MX 20 mail2.example.net.
+@ 300 IN TXT "v=spf1 -all"
+www 300 IN A 192.0.2.1
+www 300 IN A 192.0.2.2
+_sip._tcp 300 IN SRV 10 5 5060 sip
+* 300 IN TXT "say \"hi\"" "${var}"
+1a 300 IN PTR host
+`
+
+func Test_WriteTerraform(t *testing.T) {
+	z := mustParse(t, terraformSample)
+
+	buf := bytes.Buffer{}
+	err := WriteTerraform(&buf, z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, `resource "dns_record" "apex_mx" {
+  zone    = "example.com."
+  name    = "@"
+  type    = "MX"
+  ttl     = 300
+  records = ["10 mail.example.com.", "20 mail2.example.net."]
+}
+
+resource "dns_record" "apex_txt" {
+  zone    = "example.com."
+  name    = "@"
+  type    = "TXT"
+  ttl     = 300
+  records = ["v=spf1 -all"]
+}
+
+resource "dns_record" "www_a" {
+  zone    = "example.com."
+  name    = "www"
+  type    = "A"
+  ttl     = 300
+  records = ["192.0.2.1", "192.0.2.2"]
+}
+
+resource "dns_record" "_sip__tcp_srv" {
+  zone    = "example.com."
+  name    = "_sip._tcp"
+  type    = "SRV"
+  ttl     = 300
+  records = ["10 5 5060 sip.example.com."]
+}
+
+resource "dns_record" "wildcard_txt" {
+  zone    = "example.com."
+  name    = "*"
+  type    = "TXT"
+  ttl     = 300
+  records = ["say \"hi\"$${var}"]
+}
+
+resource "dns_record" "_1a_ptr" {
+  zone    = "example.com."
+  name    = "1a"
+  type    = "PTR"
+  ttl     = 300
+  records = ["host.example.com."]
+}
+`, buf.String())
+}
+
+func Test_WriteTerraform_Route53(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+@ 300 IN TXT "v=DKIM1; k=rsa" "p=abc"
+a.b 300 IN A 192.0.2.1
+a_b 300 IN A 192.0.2.2
+long 300 IN TXT "`+strings.Repeat("x", 300)+`"
+`)
+
+	buf := bytes.Buffer{}
+	err := WriteTerraform(&buf, z, "example.com.",
+		WithTerraformSchema(TerraformRoute53),
+		WithTerraformZoneID("aws_route53_zone.main.zone_id"),
+	)
+	require.Nil(t, err)
+	assert.Equal(t, `resource "aws_route53_record" "apex_txt" {
+  zone_id = aws_route53_zone.main.zone_id
+  name    = "example.com."
+  type    = "TXT"
+  ttl     = 300
+  records = ["v=DKIM1; k=rsa\"\"p=abc"]
+}
+
+resource "aws_route53_record" "a_b_a" {
+  zone_id = aws_route53_zone.main.zone_id
+  name    = "a.b.example.com."
+  type    = "A"
+  ttl     = 300
+  records = ["192.0.2.1"]
+}
+
+resource "aws_route53_record" "a_b_a_2" {
+  zone_id = aws_route53_zone.main.zone_id
+  name    = "a_b.example.com."
+  type    = "A"
+  ttl     = 300
+  records = ["192.0.2.2"]
+}
+
+resource "aws_route53_record" "long_txt" {
+  zone_id = aws_route53_zone.main.zone_id
+  name    = "long.example.com."
+  type    = "TXT"
+  ttl     = 300
+  records = ["`+strings.Repeat("x", 255)+`\"\"`+strings.Repeat("x", 45)+`"]
+}
+`, buf.String())
+}
+
+func Test_WriteTerraform_Errors(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+www.example.net. 300 IN A 192.0.2.1
+www 300 CH TXT "foo"
+`)
+	buf := bytes.Buffer{}
+	err := WriteTerraform(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrOutOfZone)
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.Empty(t, buf.String())
+
+	err = WriteTerraform(&buf, z, "example.com.", WithTerraformSchema(TerraformSchema(7)))
+	assert.ErrorContains(t, err, "unknown terraform schema")
+}
+
+func Test_hclString(t *testing.T) {
+	assert.Equal(t, `"a\\b \"c\" \n %%{x} $x $${"`, hclString("a\\b \"c\" \n %{x} $x ${"))
+	assert.Equal(t, `"\u0001"`, hclString("\x01"))
+}
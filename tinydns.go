@@ -0,0 +1,616 @@
+package zone
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The default TTLs applied by tinydns-data.
+const (
+	tinyDNSTtlNS       = 259200
+	tinyDNSTtlPositive = 86400
+	tinyDNSTtlNegative = 2560
+)
+
+// tinyDNSTextChunk is the number of bytes tinydns-data places in each
+// <character-string> of a `'` record.
+const tinyDNSTextChunk = 127
+
+// tinyDNSSoaDefaults are the refresh, retry, expire, and minimum values
+// tinydns-data uses for SOA records that do not specify them.
+var tinyDNSSoaDefaults = []string{"16384", "2048", "1048576", "2560"}
+
+// tinyDNSGenericTypes lists the record types that tinydns-data refuses in
+// `:` lines.
+var tinyDNSGenericTypes = map[uint16]bool{2: true, 5: true, 6: true, 12: true, 15: true, 252: true}
+
+// tinyDNSSupports determines if tinydns-data can represent records of a
+// type, either with a line of their own or with a generic `:` line.
+func tinyDNSSupports(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "SOA", "NS", "MX", "A", "AAAA", "CNAME", "PTR", "TXT":
+		return true
+	}
+	code, ok := recordTypeCode(recordType)
+	return ok == true && tinyDNSGenericTypes[code] == false
+}
+
+const (
+	// MetadataTinyDNSTimestamp is the [ResourceRecord.Metadata] key of the
+	// TAI64 timestamp field of a tinydns-data line.
+	MetadataTinyDNSTimestamp = "tinydns.timestamp"
+
+	// MetadataTinyDNSLocation is the [ResourceRecord.Metadata] key of the
+	// location field of a tinydns-data line.
+	MetadataTinyDNSLocation = "tinydns.location"
+)
+
+type TinyDNSOption func(parser *tinyDNSParser) error
+
+type tinyDNSParser struct {
+	serial    uint32
+	hasSerial bool
+}
+
+// WithTinyDNSSerial sets the serial number of SOA records that do not
+// specify one. By default, tinydns-data uses the modification time of the
+// data file. The same is done when the reader is a file, and the current
+// time is used otherwise.
+func WithTinyDNSSerial(serial uint32) TinyDNSOption {
+	return func(parser *tinyDNSParser) error {
+		parser.serial = serial
+		parser.hasSerial = true
+		return nil
+	}
+}
+
+// ParseTinyDNS reads the lines of a [tinydns-data] file into a [Zone]. Every
+// record the lines define is produced, following the semantics of
+// tinydns-data: e.g. a `.` line results in SOA, NS, and A records, and an
+// `=` line results in an A record and the matching PTR record. When the
+// first record is a SOA record it becomes the zone's SOA.
+//
+// The timestamp and location fields are kept in the [ResourceRecord.Metadata]
+// of each record. Location definitions (`%` lines), and disabled records
+// (`-` lines), are skipped. AAAA records may be given with the `3` and `6`
+// lines supported by common IPv6 patches.
+//
+// [tinydns-data]: https://cr.yp.to/djbdns/tinydns-data.html
+func ParseTinyDNS(reader io.Reader, opts ...TinyDNSOption) (*Zone, error) {
+	parser := &tinyDNSParser{}
+	for _, opt := range opts {
+		err := opt(parser)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if parser.hasSerial == false {
+		parser.serial = uint32(time.Now().Unix())
+		if file, ok := reader.(interface{ Stat() (fs.FileInfo, error) }); ok == true {
+			info, err := file.Stat()
+			if err == nil {
+				parser.serial = uint32(info.ModTime().Unix())
+			}
+		}
+	}
+
+	result := &Zone{
+		Records: make([]ResourceRecord, 0),
+	}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		if line == "" {
+			continue
+		}
+
+		records, err := parser.parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("parse tinydns data: line %d: %w", lineNumber, err)
+		}
+		for _, rr := range records {
+			rr.Position = Position{StartLine: lineNumber, EndLine: lineNumber}
+			if result.SOA.IsEmpty() && len(result.Records) == 0 && rr.Type == "SOA" {
+				result.SOA = rr
+				continue
+			}
+			result.Records = append(result.Records, rr)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("parse tinydns data: %w", err)
+	}
+	return result, nil
+}
+
+// parseLine converts a single tinydns-data line into the records it defines.
+func (parser *tinyDNSParser) parseLine(line string) ([]ResourceRecord, error) {
+	fields := strings.Split(line[1:], ":")
+	field := func(i int) string {
+		if i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+	name := tinyDNSName(field(0))
+
+	// rr builds a record from the TTL field at index i, which is followed by
+	// the timestamp and location fields.
+	rr := func(recordType string, i int, defaultTtl int, values ...string) (ResourceRecord, error) {
+		result := ResourceRecord{
+			Name:   name,
+			Class:  defaultClass,
+			Type:   recordType,
+			TTL:    defaultTtl,
+			HasTTL: true,
+			Values: values,
+		}
+		if ttl := field(i); ttl != "" {
+			value, err := strconv.ParseUint(ttl, 10, 32)
+			if err != nil {
+				return result, fmt.Errorf("invalid ttl %s", ttl)
+			}
+			result.TTL = int(value)
+		}
+		if timestamp := field(i + 1); timestamp != "" {
+			result.Metadata = map[string]string{MetadataTinyDNSTimestamp: timestamp}
+		}
+		if location := field(i + 2); location != "" {
+			if result.Metadata == nil {
+				result.Metadata = make(map[string]string)
+			}
+			result.Metadata[MetadataTinyDNSLocation] = location
+		}
+		return result, nil
+	}
+
+	switch line[0] {
+	case '#', '-', '%':
+		return nil, nil
+
+	case '.', '&':
+		host := tinyDNSHost(field(2), "ns", field(0))
+		ns, err := rr("NS", 3, tinyDNSTtlNS, host)
+		if err != nil {
+			return nil, err
+		}
+		result := make([]ResourceRecord, 0, 3)
+		if line[0] == '.' {
+			soa, _ := rr("SOA", 3, tinyDNSTtlNegative)
+			if ns.TTL != 0 {
+				soa.TTL = tinyDNSTtlNegative
+			}
+			soa.Values = append([]string{host, "hostmaster." + name, strconv.FormatUint(uint64(parser.serial), 10)}, tinyDNSSoaDefaults...)
+			result = append(result, soa)
+		}
+		result = append(result, ns)
+		return tinyDNSAddress(result, ns, host, field(1))
+
+	case 'Z':
+		soa, err := rr("SOA", 8, tinyDNSTtlNegative, tinyDNSName(field(1)), tinyDNSName(field(2)))
+		if err != nil {
+			return nil, err
+		}
+		defaults := append([]string{strconv.FormatUint(uint64(parser.serial), 10)}, tinyDNSSoaDefaults...)
+		for i, value := range defaults {
+			if field(3+i) != "" {
+				value = field(3 + i)
+			}
+			if _, err = strconv.ParseUint(value, 10, 32); err != nil {
+				return nil, fmt.Errorf("invalid SOA value %s", value)
+			}
+			soa.Values = append(soa.Values, value)
+		}
+		return []ResourceRecord{soa}, nil
+
+	case '+', '=', '3', '6':
+		recordType := "A"
+		if line[0] == '3' || line[0] == '6' {
+			recordType = "AAAA"
+		}
+		a, err := rr(recordType, 2, tinyDNSTtlPositive)
+		if err != nil {
+			return nil, err
+		}
+		result, err := tinyDNSAddress(nil, a, name, field(1))
+		if err != nil || len(result) == 0 || (line[0] != '=' && line[0] != '6') {
+			return result, err
+		}
+		ptr := a
+		ptr.Name, ptr.Type, ptr.Values = reverseName(result[0].Values[0]), "PTR", []string{name}
+		ptr.Metadata = maps.Clone(a.Metadata)
+		return append(result, ptr), nil
+
+	case '@':
+		host := tinyDNSHost(field(2), "mx", field(0))
+		distance := field(3)
+		if distance == "" {
+			distance = "0"
+		}
+		if _, err := strconv.ParseUint(distance, 10, 16); err != nil {
+			return nil, fmt.Errorf("invalid MX distance %s", distance)
+		}
+		mx, err := rr("MX", 4, tinyDNSTtlPositive, distance, host)
+		if err != nil {
+			return nil, err
+		}
+		return tinyDNSAddress([]ResourceRecord{mx}, mx, host, field(1))
+
+	case 'C', '^':
+		recordType := "CNAME"
+		if line[0] == '^' {
+			recordType = "PTR"
+		}
+		record, err := rr(recordType, 2, tinyDNSTtlPositive, tinyDNSName(field(1)))
+		return []ResourceRecord{record}, err
+
+	case '\'':
+		text := tinyDNSUnescape(field(1))
+		values := make([]string, 0, len(text)/tinyDNSTextChunk+1)
+		for len(text) > 0 {
+			chunk := text[:min(len(text), tinyDNSTextChunk)]
+			values = append(values, quoteCharacterString(string(chunk)))
+			text = text[len(chunk):]
+		}
+		record, err := rr("TXT", 2, tinyDNSTtlPositive, values...)
+		return []ResourceRecord{record}, err
+
+	case ':':
+		code, err := strconv.ParseUint(field(1), 10, 16)
+		if err != nil || code == 0 {
+			return nil, fmt.Errorf("invalid record type %s", field(1))
+		}
+		if tinyDNSGenericTypes[uint16(code)] == true {
+			return nil, fmt.Errorf("record type %d is not allowed in generic records", code)
+		}
+		recordType := recordTypeName(uint16(code))
+		values, err := unpackRdata(recordType, tinyDNSUnescape(field(2)))
+		if err != nil {
+			return nil, err
+		}
+		record, err := rr(recordType, 3, tinyDNSTtlPositive, values...)
+		return []ResourceRecord{record}, err
+	}
+
+	return nil, fmt.Errorf("unknown line type %q", line[0])
+}
+
+// tinyDNSAddress appends an address record for host to records when ip is
+// present. The record takes its TTL and metadata from template.
+func tinyDNSAddress(records []ResourceRecord, template ResourceRecord, host string, ip string) ([]ResourceRecord, error) {
+	if ip == "" {
+		return records, nil
+	}
+
+	recordType := "A"
+	if template.Type == "AAAA" {
+		recordType = "AAAA"
+		// Addresses are written as 32 hexadecimal digits without separators.
+		if len(ip) != 32 {
+			return nil, fmt.Errorf("invalid IPv6 address %s", ip)
+		}
+		groups := make([]string, 0, 8)
+		for i := 0; i < len(ip); i += 4 {
+			groups = append(groups, ip[i:i+4])
+		}
+		ip = strings.Join(groups, ":")
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || addr.Is4() != (recordType == "A") {
+		return nil, fmt.Errorf("invalid address %s", ip)
+	}
+
+	template.Name, template.Type, template.Values = host, recordType, []string{addr.String()}
+	template.Metadata = maps.Clone(template.Metadata)
+	return append(records, template), nil
+}
+
+// tinyDNSHost resolves the server name of `.`, `&`, and `@` lines. A name
+// without a dot is taken to be a label under `<label>.<fqdn>`, e.g. `a`
+// becomes `a.ns.example.com` for `label` ns.
+func tinyDNSHost(host string, label string, fqdn string) string {
+	if strings.Contains(host, ".") == false {
+		host = host + "." + label + "." + fqdn
+	}
+	return tinyDNSName(host)
+}
+
+// tinyDNSName converts a name from a tinydns-data line into an absolute
+// name in presentation format.
+func tinyDNSName(field string) string {
+	name := strings.Builder{}
+	for _, label := range bytes.Split(tinyDNSUnescape(field), []byte(".")) {
+		if len(label) > 0 {
+			writeLabel(&name, label)
+		}
+	}
+	if name.Len() == 0 {
+		return "."
+	}
+	return name.String()
+}
+
+// tinyDNSUnescape resolves the `\ooo` octal escapes of a tinydns-data field.
+// A backslash that is not followed by octal digits escapes the next byte.
+func tinyDNSUnescape(field string) []byte {
+	result := make([]byte, 0, len(field))
+	for i := 0; i < len(field); i++ {
+		if field[i] != escapeByte || i+1 == len(field) {
+			result = append(result, field[i])
+			continue
+		}
+
+		value, digits := 0, 0
+		for digits < 3 && i+1+digits < len(field) && field[i+1+digits] >= '0' && field[i+1+digits] <= '7' {
+			value = value*8 + int(field[i+1+digits]-'0')
+			digits += 1
+		}
+		if digits == 0 {
+			result = append(result, field[i+1])
+			i += 1
+			continue
+		}
+		result = append(result, byte(value))
+		i += digits
+	}
+	return result
+}
+
+// tinyDNSEscape writes data as a tinydns-data field, escaping colons,
+// backslashes, and bytes outside of printable ASCII as `\ooo`.
+func tinyDNSEscape(data []byte) string {
+	str := strings.Builder{}
+	for _, b := range data {
+		if b == ':' || b == escapeByte || b < 0x20 || b > 0x7e {
+			str.WriteString(fmt.Sprintf("\\%03o", b))
+			continue
+		}
+		str.WriteByte(b)
+	}
+	return str.String()
+}
+
+// tinyDNSNameField renders an absolute name in presentation format as a
+// tinydns-data field.
+func tinyDNSNameField(name string) (string, error) {
+	wire, err := packName(nil, name)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", err, ErrInvalidRdata)
+	}
+
+	labels := make([]string, 0)
+	for offset := 0; wire[offset] != 0; offset += 1 + int(wire[offset]) {
+		label := tinyDNSEscape(wire[offset+1 : offset+1+int(wire[offset])])
+		labels = append(labels, strings.ReplaceAll(label, ".", `\056`))
+	}
+	return strings.Join(labels, "."), nil
+}
+
+// reverseName determines the name of the PTR record for an address.
+func reverseName(address string) string {
+	addr, _ := netip.ParseAddr(address)
+	data := addr.AsSlice()
+	labels := make([]string, 0, 2*len(data)+2)
+	for i := len(data) - 1; i >= 0; i-- {
+		if addr.Is4() {
+			labels = append(labels, strconv.Itoa(int(data[i])))
+			continue
+		}
+		labels = append(labels, strconv.FormatUint(uint64(data[i]&0x0f), 16), strconv.FormatUint(uint64(data[i]>>4), 16))
+	}
+	if addr.Is4() {
+		return strings.Join(labels, ".") + ".in-addr.arpa."
+	}
+	return strings.Join(labels, ".") + ".ip6.arpa."
+}
+
+// WriteTinyDNS writes the records of a zone as [tinydns-data] lines.
+// Relative names are qualified with origin.
+//
+// A and AAAA records that are matched by a PTR record for their address,
+// with the same TTL and metadata, are written as a single `=` or `6` line.
+// TXT records are written as `'` lines when their strings match the
+// division tinydns-data would produce, and record types without a
+// dedicated line are written as generic `:` lines. The timestamp and
+// location in the [ResourceRecord.Metadata] of each record are preserved.
+//
+// Records that tinydns-data cannot represent, e.g. records of a class other
+// than IN, are reported as [RecordError] values in the returned error, and
+// nothing is written.
+//
+// [tinydns-data]: https://cr.yp.to/djbdns/tinydns-data.html
+func WriteTinyDNS(w io.Writer, z *Zone, origin string) error {
+	if origin != "" {
+		origin = fqdn(origin)
+	}
+
+	records := z.AllRecords()
+	for i, rr := range records {
+		rr = qualifyRdata(rr, origin)
+		rr.Name = qualifyName(rr.Name, origin)
+		records[i] = rr
+	}
+
+	// Pair every address record with the first unused PTR record that
+	// tinydns-data would generate for it.
+	paired := make(map[int]bool)
+	for i, rr := range records {
+		recordType := strings.ToUpper(rr.Type)
+		if (recordType != "A" && recordType != "AAAA") || len(rr.Values) != 1 {
+			continue
+		}
+		if _, err := netip.ParseAddr(rr.Values[0]); err != nil {
+			continue
+		}
+		for j, ptr := range records {
+			if paired[j] == false && tinyDNSPairs(rr, ptr) {
+				paired[i], paired[j] = true, true
+				break
+			}
+		}
+	}
+
+	var errs []error
+	buf := bytes.Buffer{}
+	for i, rr := range records {
+		if paired[i] && strings.EqualFold(rr.Type, "PTR") {
+			continue
+		}
+		line, err := tinyDNSLine(rr, paired[i])
+		if err != nil {
+			errs = append(errs, &RecordError{Record: rr, Err: err})
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// tinyDNSPairs determines if ptr is the PTR record tinydns-data generates
+// for the address record rr.
+func tinyDNSPairs(rr ResourceRecord, ptr ResourceRecord) bool {
+	return strings.EqualFold(ptr.Type, "PTR") &&
+		len(ptr.Values) == 1 &&
+		strings.EqualFold(ptr.Values[0], rr.Name) &&
+		strings.EqualFold(ptr.Name, reverseName(rr.Values[0])) &&
+		strings.EqualFold(ptr.Class, rr.Class) &&
+		ptr.TTL == rr.TTL &&
+		ptr.Metadata[MetadataTinyDNSTimestamp] == rr.Metadata[MetadataTinyDNSTimestamp] &&
+		ptr.Metadata[MetadataTinyDNSLocation] == rr.Metadata[MetadataTinyDNSLocation]
+}
+
+// tinyDNSLine renders a record as a tinydns-data line. Address records with
+// withPtr set are rendered as lines that also generate their PTR record.
+func tinyDNSLine(rr ResourceRecord, withPtr bool) (string, error) {
+	if strings.EqualFold(rr.Class, defaultClass) == false {
+		return "", ErrUnsupportedRecord
+	}
+	name, err := tinyDNSNameField(rr.Name)
+	if err != nil {
+		return "", err
+	}
+	names := func(values ...string) ([]string, error) {
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			field, err := tinyDNSNameField(value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, field)
+		}
+		return result, nil
+	}
+
+	var fields []string
+	recordType := strings.ToUpper(rr.Type)
+	switch recordType {
+	case "SOA":
+		if len(rr.Values) != 7 {
+			return "", fmt.Errorf("expected 7 values: %w", ErrInvalidRdata)
+		}
+		fields, err = names(rr.Values[0], rr.Values[1])
+		fields = append([]string{"Z" + name}, append(fields, rr.Values[2:]...)...)
+
+	case "NS", "MX":
+		if (recordType == "NS" && len(rr.Values) != 1) || (recordType == "MX" && len(rr.Values) != 2) {
+			return "", fmt.Errorf("unexpected number of values: %w", ErrInvalidRdata)
+		}
+		fields, err = names(rr.Values[len(rr.Values)-1])
+		if err == nil && strings.Contains(fields[0], ".") == false {
+			// tinydns-data would take the host to be a label under the name.
+			return "", ErrUnsupportedRecord
+		}
+		if recordType == "NS" {
+			fields = []string{"&" + name, "", fields[0]}
+		} else {
+			fields = []string{"@" + name, "", fields[0], rr.Values[0]}
+		}
+
+	case "A", "AAAA":
+		addr, parseErr := netip.ParseAddr(strings.Join(rr.Values, " "))
+		if parseErr != nil || addr.Is4() != (recordType == "A") {
+			return "", fmt.Errorf("invalid address: %w", ErrInvalidRdata)
+		}
+		prefix := map[bool]string{false: "+", true: "="}[withPtr]
+		value := addr.String()
+		if recordType == "AAAA" {
+			prefix = map[bool]string{false: "3", true: "6"}[withPtr]
+			value = fmt.Sprintf("%x", addr.AsSlice())
+		}
+		fields = []string{prefix + name, value}
+
+	case "CNAME", "PTR":
+		if len(rr.Values) != 1 {
+			return "", fmt.Errorf("expected 1 value: %w", ErrInvalidRdata)
+		}
+		fields, err = names(rr.Values[0])
+		fields = append([]string{map[string]string{"CNAME": "C", "PTR": "^"}[recordType] + name}, fields...)
+
+	case "TXT":
+		strs, parseErr := parseCharacterStrings(rr.Values)
+		if parseErr != nil {
+			return "", parseErr
+		}
+		text := strings.Join(strs, "")
+		if tinyDNSChunked(strs, text) {
+			fields = []string{"'" + name, tinyDNSEscape([]byte(text))}
+			break
+		}
+		fields, err = tinyDNSGenericFields(name, rr)
+
+	default:
+		fields, err = tinyDNSGenericFields(name, rr)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fields = append(fields, strconv.Itoa(rr.TTL), rr.Metadata[MetadataTinyDNSTimestamp], rr.Metadata[MetadataTinyDNSLocation])
+	for fields[len(fields)-1] == "" {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, ":"), nil
+}
+
+// tinyDNSChunked determines if the strings of a TXT record are the strings
+// tinydns-data produces for text.
+func tinyDNSChunked(strs []string, text string) bool {
+	for i, str := range strs {
+		if str != text[min(i*tinyDNSTextChunk, len(text)):min((i+1)*tinyDNSTextChunk, len(text))] {
+			return false
+		}
+	}
+	return len(strs) == (len(text)+tinyDNSTextChunk-1)/tinyDNSTextChunk
+}
+
+// tinyDNSGenericFields renders a record as the fields of a generic `:` line.
+func tinyDNSGenericFields(name string, rr ResourceRecord) ([]string, error) {
+	code, ok := recordTypeCode(rr.Type)
+	if ok == false {
+		return nil, ErrUnsupportedRecord
+	}
+	if tinyDNSGenericTypes[code] == true {
+		return nil, ErrUnsupportedRecord
+	}
+	rdata, err := packRdata(rr)
+	if err != nil {
+		return nil, err
+	}
+	return []string{":" + name, strconv.Itoa(int(code)), tinyDNSEscape(rdata)}, nil
+}
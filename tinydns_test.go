@@ -0,0 +1,165 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func Test_ParseTinyDNS(t *testing.T) {
+	data := `# example.com
+.example.com:192.0.2.53:a:259200
+&example.com::ns2.example.net
+=www.example.com:192.0.2.1:300:4000000066b1a2c3:ex
++ftp.example.com:192.0.2.2
+@example.com:192.0.2.25:mx1:10
+Cmail.example.com:www.example.com
+'example.com:v=spf1 ip4\072192.0.2.0/24 -all:600
+^3.2.0.192.in-addr.arpa:host.example.com
+:example.com:257:\000\005issueletsencrypt.org
+6v6.example.com:20010db8000000000000000000000001
+%ex:192.0.2
+-disabled.example.com:192.0.2.9
+Zexample.net:ns1.example.net:hostmaster.example.net:2024010101
+`
+	z, err := ParseTinyDNS(strings.NewReader(data), WithTinyDNSSerial(1700000000))
+	require.Nil(t, err)
+
+	found := []string{z.SOA.String()}
+	for _, rr := range z.Records {
+		found = append(found, rr.String())
+	}
+	assert.Equal(t, []string{
+		"example.com. 2560 IN SOA a.ns.example.com. hostmaster.example.com. 1700000000 16384 2048 1048576 2560\n",
+		"example.com. 259200 IN NS a.ns.example.com.\n",
+		"a.ns.example.com. 259200 IN A 192.0.2.53\n",
+		"example.com. 259200 IN NS ns2.example.net.\n",
+		"www.example.com. 300 IN A 192.0.2.1\n",
+		"1.2.0.192.in-addr.arpa. 300 IN PTR www.example.com.\n",
+		"ftp.example.com. 86400 IN A 192.0.2.2\n",
+		"example.com. 86400 IN MX 10 mx1.mx.example.com.\n",
+		"mx1.mx.example.com. 86400 IN A 192.0.2.25\n",
+		"mail.example.com. 86400 IN CNAME www.example.com.\n",
+		`example.com. 600 IN TXT "v=spf1 ip4:192.0.2.0/24 -all"` + "\n",
+		"3.2.0.192.in-addr.arpa. 86400 IN PTR host.example.com.\n",
+		`example.com. 86400 IN CAA 0 issue "letsencrypt.org"` + "\n",
+		"v6.example.com. 86400 IN AAAA 2001:db8::1\n",
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa. 86400 IN PTR v6.example.com.\n",
+		"example.net. 2560 IN SOA ns1.example.net. hostmaster.example.net. 2024010101 16384 2048 1048576 2560\n",
+	}, found)
+
+	assert.Equal(t, map[string]string{
+		MetadataTinyDNSTimestamp: "4000000066b1a2c3",
+		MetadataTinyDNSLocation:  "ex",
+	}, z.Records[4].Metadata)
+	assert.Equal(t, Position{StartLine: 4, EndLine: 4}, z.Records[4].Position)
+
+	_, err = ParseTinyDNS(strings.NewReader("+www.example.com:192.0.2.1\n!foo\n"))
+	assert.ErrorContains(t, err, "line 2: unknown line type '!'")
+
+	_, err = ParseTinyDNS(strings.NewReader(":example.com:15:\\000\\012\n"))
+	assert.ErrorContains(t, err, "not allowed in generic records")
+}
+
+func Test_ParseTinyDNS_Escapes(t *testing.T) {
+	z, err := ParseTinyDNS(strings.NewReader(`'a\072b.example.com:line\012two\\:60`))
+	require.Nil(t, err)
+	assert.Equal(t, `a:b.example.com.`, z.Records[0].Name)
+	assert.Equal(t, []string{`"line\010two\\"`}, z.Records[0].Values)
+
+	z, err = ParseTinyDNS(strings.NewReader(`'long.example.com:` + strings.Repeat("x", 200)))
+	require.Nil(t, err)
+	assert.Equal(t, []string{`"` + strings.Repeat("x", 127) + `"`, `"` + strings.Repeat("x", 73) + `"`}, z.Records[0].Values)
+}
+
+func Test_WriteTinyDNS(t *testing.T) {
+	z := mustParse(t, `$ORIGIN example.com.
+@ 2560 IN SOA ns1 hostmaster 1 16384 2048 1048576 2560
+@ 259200 IN NS ns1
+www 300 IN A 192.0.2.1
+1.2.0.192.in-addr.arpa. 300 IN PTR www
+ftp 300 IN A 192.0.2.2
+2.2.0.192.in-addr.arpa. 600 IN PTR ftp
+@ 300 IN MX 10 mail
+mail 300 IN CNAME www
+@ 300 IN TXT "v=spf1 ip4:192.0.2.0/24 -all"
+split 300 IN TXT "a" "b"
+_sip._tcp 300 IN SRV 10 5 5060 sip
+v6 300 IN AAAA 2001:db8::1
+`)
+	z.Records[1].Metadata = map[string]string{MetadataTinyDNSLocation: "ex"}
+	z.Records[2].Metadata = map[string]string{MetadataTinyDNSLocation: "ex"}
+
+	buf := bytes.Buffer{}
+	err := WriteTinyDNS(&buf, z, "example.com")
+	require.Nil(t, err)
+	assert.Equal(t, `Zexample.com:ns1.example.com:hostmaster.example.com:1:16384:2048:1048576:2560:2560
+&example.com::ns1.example.com:259200
+=www.example.com:192.0.2.1:300::ex
++ftp.example.com:192.0.2.2:300
+^2.2.0.192.in-addr.arpa:ftp.example.com:600
+@example.com::mail.example.com:10:300
+Cmail.example.com:www.example.com:300
+'example.com:v=spf1 ip4\072192.0.2.0/24 -all:300
+:split.example.com:16:\001a\001b:300
+:_sip._tcp.example.com:33:\000\012\000\005\023\304\003sip\007example\003com\000:300
+3v6.example.com:20010db8000000000000000000000001:300
+`, buf.String())
+
+	z = mustParse(t, `$ORIGIN example.com.
+@ 300 CH TXT "foo"
+@ 300 IN NS localhost.
+@ 300 IN LOC 52 22 23.000 N 4 53 32.000 E -2.00m 0.00m 10000m 10m
+`)
+	buf.Reset()
+	err = WriteTinyDNS(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.Equal(t, 3, strings.Count(err.Error(), ErrUnsupportedRecord.Error()))
+	assert.Empty(t, buf.String())
+}
+
+func Test_TinyDNS_RoundTrip(t *testing.T) {
+	tests := []struct {
+		dir  string
+		name string
+	}{
+		{"testdata", "simple.txt"},
+		{"testdata", "opendkim_1024.sample.txt"},
+		{"testdata", "opendkim_2048.sample.txt"},
+		{"testdata/bind9", "master1.txt"},
+		{"testdata/bind9", "master3.txt"},
+		{"testdata/bind9", "master4.txt"},
+		{"testdata/bind9", "master17.txt"},
+	}
+
+	zp, _ := NewZoneParser()
+	for _, test := range tests {
+		t.Logf("testing fixture: %s/%s", test.dir, test.name)
+		fixtures, err := readFixtures(test.dir)
+		require.Nil(t, err)
+		expected, err := zp.Parse(fixtures[test.name].input)
+		require.Nil(t, err)
+		closeFixtures(fixtures)
+
+		buf := bytes.Buffer{}
+		err = WriteTinyDNS(&buf, expected, "example.com.")
+		require.Nil(t, err)
+		found, err := ParseTinyDNS(&buf)
+		require.Nil(t, err)
+
+		assert.Empty(t, Diff(tinyDNSSemanticZone(expected), tinyDNSSemanticZone(found)))
+	}
+}
+
+// tinyDNSSemanticZone normalizes a zone for comparison with a zone that has
+// been converted to tinydns-data and back. Names are qualified and compared
+// without regard to case, and TXT strings are joined.
+func tinyDNSSemanticZone(z *Zone) *Zone {
+	result := semanticZone(&Zone{Records: append([]ResourceRecord{z.SOA}, z.Records...)}, "example.com.")
+	for i := range result.Records {
+		result.Records[i].Name = strings.ToLower(result.Records[i].Name)
+	}
+	return result
+}
@@ -1,9 +1,6 @@
 package zone
 
-import (
-	"bytes"
-	"unicode"
-)
+import "unicode"
 
 // tokenizeLine parses a line into a set of record tokens. A record token
 // is a sequence of non-whitespace characters, or a sequence of quoted
@@ -45,11 +42,9 @@ func tokenizeLine(line []byte) [][]byte {
 
 	if len(token) > 0 {
 		// If the line did not end with spaces, then the last token would not have
-		// been appended to the result. Also, if the line was a single token then
-		// the token would not have been appended, i.e. `len(result) == 0`.
-		if len(result) == 0 || bytes.Equal(result[len(result)-1], token) == false {
-			result = append(result, token)
-		}
+		// been appended to the result. Tokens are reset once appended, so a
+		// remaining token is always new even when it repeats the previous one.
+		result = append(result, token)
 	}
 
 	return result
@@ -1,7 +1,9 @@
 package zone
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -63,3 +65,15 @@ func Test_tokenizeLine(t *testing.T) {
 	found = tokenizeLine(line)
 	assert.Equal(t, expected, found)
 }
+
+func Test_tokenizeLine_RepeatedFinalToken(t *testing.T) {
+	// A final token that repeated the previous token used to be dropped.
+	expected := [][]byte{[]byte("3"), []byte("3")}
+	assert.Equal(t, expected, tokenizeLine([]byte("3 3")))
+
+	// e.g. when the RDATA of a JSON record is split into values.
+	var rr ResourceRecord
+	err := json.Unmarshal([]byte(`{"TYPEname":"SOA","rdataSOA":"ns. host. 1 3600 3600 3600 3600"}`), &rr)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"ns.", "host.", "1", "3600", "3600", "3600", "3600"}, rr.Values)
+}
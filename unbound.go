@@ -0,0 +1,145 @@
+package zone
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+type UnboundOption func(exporter *unboundExporter) error
+
+type unboundExporter struct {
+	zoneType string
+}
+
+// WithUnboundZoneType sets the type of the `local-zone` statement, e.g.
+// `transparent` to let queries for names without local data be resolved
+// normally. The default is `static`.
+func WithUnboundZoneType(zoneType string) UnboundOption {
+	return func(exporter *unboundExporter) error {
+		if zoneType == "" || strings.ContainsAny(zoneType, " \t\"'") {
+			return fmt.Errorf("invalid unbound local-zone type: %q", zoneType)
+		}
+		exporter.zoneType = zoneType
+		return nil
+	}
+}
+
+// WriteUnbound writes the records of a zone as [Unbound] `local-zone`,
+// `local-data`, and `local-data-ptr` statements for the zone at apex.
+// Relative names are qualified with apex. PTR records whose owner is the
+// reverse name of an address are written as `local-data-ptr` statements.
+//
+// Unbound does not expand wildcards, and only serves class IN data. Such
+// records are reported as [RecordError] values, wrapping
+// [ErrUnsupportedRecord], in the returned error. All other records are
+// still written.
+//
+// [Unbound]: https://unbound.docs.nlnetlabs.nl/en/latest/manpages/unbound.conf.html
+func WriteUnbound(w io.Writer, z *Zone, apex string, opts ...UnboundOption) error {
+	exporter := &unboundExporter{
+		zoneType: "static",
+	}
+	for _, opt := range opts {
+		err := opt(exporter)
+		if err != nil {
+			return err
+		}
+	}
+
+	apex = fqdn(apex)
+	records := z.AllRecords()
+
+	var errs []error
+	buf := bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf("local-zone: %s %s\n", unboundString(apex), exporter.zoneType))
+	for _, rr := range records {
+		rr = qualifyRdata(rr, apex)
+		rr.Name = qualifyName(rr.Name, apex)
+		if strings.EqualFold(rr.Class, defaultClass) == false || isWildcardName(rr.Name) {
+			errs = append(errs, &RecordError{Record: rr, Err: ErrUnsupportedRecord})
+			continue
+		}
+
+		if strings.EqualFold(rr.Type, "PTR") && len(rr.Values) == 1 {
+			if addr, ok := reverseAddress(rr.Name); ok == true {
+				data := addr.String() + " " + strconv.Itoa(rr.TTL) + " " + rr.Values[0]
+				buf.WriteString("local-data-ptr: " + unboundString(data) + "\n")
+				continue
+			}
+		}
+
+		data := strings.Join(append([]string{rr.Name, strconv.Itoa(rr.TTL), strings.ToUpper(rr.Class), strings.ToUpper(rr.Type)}, rr.Values...), " ")
+		buf.WriteString("local-data: " + unboundString(data) + "\n")
+	}
+
+	_, err := buf.WriteTo(w)
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// unboundString quotes a statement value. Values that contain double quotes,
+// e.g. TXT records, are enclosed in single quotes, with single quotes
+// written as `\039` escapes.
+func unboundString(value string) string {
+	if strings.Contains(value, `"`) == false {
+		return `"` + value + `"`
+	}
+	return `'` + strings.ReplaceAll(value, `'`, `\039`) + `'`
+}
+
+// isWildcardName determines if a domain name is a wildcard, e.g.
+// `*.example.com.`.
+func isWildcardName(name string) bool {
+	return name == "*" || strings.HasPrefix(name, "*.")
+}
+
+// reverseAddress determines the address whose reverse name, in
+// `in-addr.arpa.` or `ip6.arpa.`, is the given name. The second return
+// value is false when the name is not a complete reverse name.
+func reverseAddress(name string) (netip.Addr, bool) {
+	name = strings.ToLower(fqdn(name))
+	var labels []string
+	var ip string
+	switch {
+	case strings.HasSuffix(name, ".in-addr.arpa."):
+		labels = strings.Split(strings.TrimSuffix(name, ".in-addr.arpa."), ".")
+		if len(labels) != 4 {
+			return netip.Addr{}, false
+		}
+		for i := len(labels) - 1; i >= 0; i-- {
+			ip += labels[i]
+			if i > 0 {
+				ip += "."
+			}
+		}
+	case strings.HasSuffix(name, ".ip6.arpa."):
+		labels = strings.Split(strings.TrimSuffix(name, ".ip6.arpa."), ".")
+		if len(labels) != 32 {
+			return netip.Addr{}, false
+		}
+		for i := len(labels) - 1; i >= 0; i-- {
+			if len(labels[i]) != 1 {
+				return netip.Addr{}, false
+			}
+			ip += labels[i]
+			if i%4 == 0 && i > 0 {
+				ip += ":"
+			}
+		}
+	default:
+		return netip.Addr{}, false
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil || reverseName(addr.String()) != name {
+		return netip.Addr{}, false
+	}
+	return addr, true
+}
@@ -0,0 +1,73 @@
+package zone
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const localResolverSample = `$ORIGIN example.com.
+@ 3600 IN SOA ns1 hostmaster 1 7200 3600 1209600 300
+@ 3600 IN NS ns1
+@ 300 IN MX 10 mail
+@ 300 IN TXT "v=spf1 -all" "it's"
+www 300 IN A 192.0.2.1
+www 300 IN AAAA 2001:db8::1
+mail 300 IN CNAME www
+_sip._tcp 300 IN SRV 10 5 5060 sip
+1.2.0.192.in-addr.arpa. 300 IN PTR www
+2.2.0.192.in-addr.arpa. 300 IN PTR host.example.net.
+`
+
+func Test_WriteUnbound(t *testing.T) {
+	z := mustParse(t, localResolverSample)
+
+	buf := bytes.Buffer{}
+	err := WriteUnbound(&buf, z, "example.com", WithUnboundZoneType("transparent"))
+	require.Nil(t, err)
+	assert.Equal(t, `local-zone: "example.com." transparent
+local-data: "example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. 1 7200 3600 1209600 300"
+local-data: "example.com. 3600 IN NS ns1.example.com."
+local-data: "example.com. 300 IN MX 10 mail.example.com."
+local-data: 'example.com. 300 IN TXT "v=spf1 -all" "it\039s"'
+local-data: "www.example.com. 300 IN A 192.0.2.1"
+local-data: "www.example.com. 300 IN AAAA 2001:db8::1"
+local-data: "mail.example.com. 300 IN CNAME www.example.com."
+local-data: "_sip._tcp.example.com. 300 IN SRV 10 5 5060 sip.example.com."
+local-data-ptr: "192.0.2.1 300 www.example.com."
+local-data-ptr: "192.0.2.2 300 host.example.net."
+`, buf.String())
+
+	z = mustParse(t, `$ORIGIN example.com.
+* 300 IN A 192.0.2.1
+www 300 CH TXT "foo"
+www 300 IN A 192.0.2.2
+`)
+	buf.Reset()
+	err = WriteUnbound(&buf, z, "example.com.")
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+	assert.Equal(t, 2, strings.Count(err.Error(), ErrUnsupportedRecord.Error()))
+	assert.Equal(t, `local-zone: "example.com." static
+local-data: "www.example.com. 300 IN A 192.0.2.2"
+`, buf.String())
+
+	err = WriteUnbound(&buf, z, "example.com.", WithUnboundZoneType("static deny"))
+	assert.ErrorContains(t, err, "invalid unbound local-zone type")
+}
+
+func Test_reverseAddress(t *testing.T) {
+	addr, ok := reverseAddress("1.2.0.192.in-addr.arpa.")
+	assert.True(t, ok)
+	assert.Equal(t, "192.0.2.1", addr.String())
+
+	addr, ok = reverseAddress(reverseName("2001:db8::1"))
+	assert.True(t, ok)
+	assert.Equal(t, "2001:db8::1", addr.String())
+
+	for _, name := range []string{"2.0.192.in-addr.arpa.", "01.2.0.192.in-addr.arpa.", "www.example.com.", "1.0.ip6.arpa."} {
+		_, ok = reverseAddress(name)
+		assert.False(t, ok, name)
+	}
+}
@@ -11,6 +11,9 @@ func compactWhiteSpace(line []byte) []byte {
 	startIdx := bytes.IndexFunc(line, isNotWhiteSpace)
 	endIdx := bytes.LastIndexFunc(line, isNotWhiteSpace)
 	result := []byte{0x20}
+	if startIdx == -1 {
+		return result
+	}
 	result = append(result, line[startIdx:endIdx+1]...)
 	return append(result, 0x20)
 }
@@ -118,3 +121,14 @@ func stripComment(line []byte) []byte {
 	}
 	return line[0:idx]
 }
+
+// lineComment finds the text of the comment in a line, without the
+// semicolon and surrounding white space. The second return value is false
+// when the line has no comment.
+func lineComment(line []byte) (string, bool) {
+	data := stripComment(line)
+	if len(data) == len(line) {
+		return "", false
+	}
+	return string(bytes.TrimSpace(line[len(data)+1:])), true
+}
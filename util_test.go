@@ -17,6 +17,8 @@ func Test_compactWhiteSpace(t *testing.T) {
 		found := compactWhiteSpace([]byte(test))
 		assert.Equal(t, expected, found)
 	}
+
+	assert.Equal(t, []byte(" "), compactWhiteSpace([]byte("\t\n")))
 }
 
 func Test_isContinuedLine(t *testing.T) {
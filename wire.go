@@ -0,0 +1,390 @@
+package zone
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// maxLabelLength is the maximum number of bytes in a single label of a
+// domain name, per RFC 1035 §2.3.4.
+const maxLabelLength = 63
+
+// wireEncoding is the wire format of a single RDATA field.
+type wireEncoding int
+
+const (
+	wireUnsupported wireEncoding = iota
+	wireName
+	wireUint8
+	wireUint16
+	wireUint32
+	wireIPv4
+	wireIPv6
+
+	// wireString is a length prefixed <character-string>.
+	wireString
+
+	// wireStrings is a sequence of length prefixed <character-string> fields
+	// that consumes the remaining RDATA.
+	wireStrings
+
+	// wireRaw is a string that consumes the remaining RDATA without a length
+	// prefix, e.g. the value of a CAA record.
+	wireRaw
+
+	wireBase64
+	wireHex
+)
+
+// wireEncodingOf determines the wire format of a field of a record type.
+// Fields whose wire format cannot be derived from their presentation format,
+// e.g. the type bitmaps of NSEC records, are unsupported.
+func wireEncodingOf(recordType string, field rdataField) wireEncoding {
+	switch strings.ToUpper(recordType) + " " + field.name {
+	case "CAA tag":
+		return wireString
+	case "CAA value", "URI target":
+		return wireRaw
+	}
+
+	switch field.kind {
+	case rdataDomain, rdataMailbox:
+		return wireName
+	case rdataUint8:
+		return wireUint8
+	case rdataUint16:
+		return wireUint16
+	case rdataUint32:
+		return wireUint32
+	case rdataIPv4:
+		return wireIPv4
+	case rdataIPv6:
+		return wireIPv6
+	case rdataString:
+		return wireString
+	case rdataStrings:
+		return wireStrings
+	case rdataBase64:
+		return wireBase64
+	case rdataHex:
+		return wireHex
+	}
+	return wireUnsupported
+}
+
+// packRdata encodes the values of a record as wire format RDATA, as
+// described in RFC 1035 §3.3. Domain names within the values must be
+// absolute, and are not compressed. Values in the RFC 3597 generic form are
+// decoded as given.
+func packRdata(rr ResourceRecord) ([]byte, error) {
+	if isGenericRdata(rr.Values) {
+		return unpackGenericRdata(rr.Values)
+	}
+
+	schema, ok := rdataSchema(rr.Type)
+	if ok == false {
+		return nil, ErrUnsupportedRecord
+	}
+	if len(rr.Values) < len(schema) ||
+		(len(rr.Values) > len(schema) && schema[len(schema)-1].kind.consumesRemainder() == false) {
+		// Text records may omit their strings entirely.
+		if isTextType(rr.Type) == false || len(rr.Values) != 0 {
+			return nil, fmt.Errorf("expected %d values: %w", len(schema), ErrInvalidRdata)
+		}
+	}
+
+	result := make([]byte, 0)
+	for i, field := range schema {
+		encoding := wireEncodingOf(rr.Type, field)
+		if encoding == wireUnsupported {
+			return nil, ErrUnsupportedRecord
+		}
+		if i >= len(rr.Values) {
+			break
+		}
+
+		var err error
+		value := rr.Values[i]
+		switch encoding {
+		case wireName:
+			result, err = packName(result, value)
+		case wireUint8, wireUint16, wireUint32:
+			result, err = packUint(result, encoding, value)
+		case wireIPv4, wireIPv6:
+			var addr netip.Addr
+			addr, err = netip.ParseAddr(value)
+			if err == nil && (addr.Is4() != (encoding == wireIPv4) || addr.Zone() != "") {
+				err = fmt.Errorf("%s is not an address of the expected family", value)
+			}
+			result = append(result, addr.AsSlice()...)
+		case wireString:
+			result, err = packCharacterString(result, value)
+		case wireStrings:
+			for _, v := range rr.Values[i:] {
+				result, err = packCharacterString(result, v)
+				if err != nil {
+					break
+				}
+			}
+		case wireRaw:
+			var str string
+			str, err = parseCharacterString(strings.Join(rr.Values[i:], " "))
+			result = append(result, str...)
+		case wireBase64:
+			var data []byte
+			data, err = base64.StdEncoding.DecodeString(strings.Join(rr.Values[i:], ""))
+			result = append(result, data...)
+		case wireHex:
+			var data []byte
+			data, err = hex.DecodeString(strings.Join(rr.Values[i:], ""))
+			result = append(result, data...)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", field.name, err, ErrInvalidRdata)
+		}
+	}
+	return result, nil
+}
+
+// unpackRdata decodes wire format RDATA into the values of a record of the
+// given type. RDATA of record types whose fields are not understood is
+// returned in the RFC 3597 generic form.
+func unpackRdata(recordType string, rdata []byte) ([]string, error) {
+	schema, ok := rdataSchema(recordType)
+	if ok == false {
+		return genericRdata(rdata), nil
+	}
+	for _, field := range schema {
+		if wireEncodingOf(recordType, field) == wireUnsupported {
+			return genericRdata(rdata), nil
+		}
+	}
+
+	result := make([]string, 0, len(schema))
+	offset := 0
+	for _, field := range schema {
+		if offset == len(rdata) && field.kind.consumesRemainder() {
+			break
+		}
+
+		var err error
+		var value string
+		switch encoding := wireEncodingOf(recordType, field); encoding {
+		case wireName:
+			value, offset, err = unpackName(rdata, offset)
+		case wireUint8, wireUint16, wireUint32:
+			value, offset, err = unpackUint(rdata, offset, encoding)
+		case wireIPv4, wireIPv6:
+			size := 4
+			if encoding == wireIPv6 {
+				size = 16
+			}
+			if offset+size > len(rdata) {
+				err = fmt.Errorf("truncated address")
+				break
+			}
+			addr, _ := netip.AddrFromSlice(rdata[offset : offset+size])
+			value, offset = addr.String(), offset+size
+		case wireString:
+			value, offset, err = unpackCharacterString(rdata, offset)
+			if err == nil && field.kind == rdataToken {
+				value, err = parseCharacterString(value)
+			}
+		case wireStrings:
+			for offset < len(rdata) && err == nil {
+				value, offset, err = unpackCharacterString(rdata, offset)
+				result = append(result, value)
+			}
+			if err == nil {
+				continue
+			}
+		case wireRaw:
+			value, offset = quoteCharacterString(string(rdata[offset:])), len(rdata)
+		case wireBase64:
+			value, offset = base64.StdEncoding.EncodeToString(rdata[offset:]), len(rdata)
+		case wireHex:
+			value, offset = strings.ToUpper(hex.EncodeToString(rdata[offset:])), len(rdata)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unpack %s %s: %s: %w", recordType, field.name, err, ErrInvalidRdata)
+		}
+		result = append(result, value)
+	}
+	if offset != len(rdata) {
+		return nil, fmt.Errorf("unpack %s: %d trailing bytes: %w", recordType, len(rdata)-offset, ErrInvalidRdata)
+	}
+	return result, nil
+}
+
+// genericRdata renders RDATA in the RFC 3597 generic form.
+func genericRdata(rdata []byte) []string {
+	result := []string{genericRdataToken, strconv.Itoa(len(rdata))}
+	if len(rdata) > 0 {
+		result = append(result, strings.ToUpper(hex.EncodeToString(rdata)))
+	}
+	return result
+}
+
+// unpackGenericRdata decodes values in the RFC 3597 generic form.
+func unpackGenericRdata(values []string) ([]byte, error) {
+	length, err := strconv.Atoi(values[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid generic RDATA length %s: %w", values[1], ErrInvalidRdata)
+	}
+	rdata, err := hex.DecodeString(strings.Join(values[2:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid generic RDATA: %s: %w", err, ErrInvalidRdata)
+	}
+	if len(rdata) != length {
+		return nil, fmt.Errorf("generic RDATA has %d bytes, expected %d: %w", len(rdata), length, ErrInvalidRdata)
+	}
+	return rdata, nil
+}
+
+// packName appends the uncompressed wire format of an absolute domain name
+// in presentation format. `\X` and `\DDD` escapes are resolved.
+func packName(buf []byte, name string) ([]byte, error) {
+	if isAbsoluteName(name) == false {
+		return nil, fmt.Errorf("%s is not an absolute name", name)
+	}
+	if name == "." {
+		return append(buf, 0), nil
+	}
+
+	label := make([]byte, 0, maxLabelLength)
+	for i := 0; i < len(name); i++ {
+		b := name[i]
+		if b == '.' {
+			if len(label) == 0 || len(label) > maxLabelLength {
+				return nil, fmt.Errorf("%s has an invalid label", name)
+			}
+			buf = append(buf, byte(len(label)))
+			buf = append(buf, label...)
+			label = label[:0]
+			continue
+		}
+		if b != escapeByte {
+			label = append(label, b)
+			continue
+		}
+
+		end := i + 2
+		if i+1 < len(name) && isDigitByte(name[i+1]) {
+			end = i + 4
+		}
+		if end > len(name) {
+			return nil, fmt.Errorf("%s has an incomplete escape", name)
+		}
+		str, err := parseCharacterString(name[i:end])
+		if err != nil {
+			return nil, err
+		}
+		label = append(label, str...)
+		i = end - 1
+	}
+	return append(buf, 0), nil
+}
+
+// unpackName decodes an uncompressed domain name at offset into its
+// presentation format, and returns the offset following the name.
+func unpackName(data []byte, offset int) (string, int, error) {
+	name := strings.Builder{}
+	for {
+		if offset >= len(data) {
+			return "", 0, fmt.Errorf("truncated name")
+		}
+		length := int(data[offset])
+		offset += 1
+		if length == 0 {
+			break
+		}
+		if length > maxLabelLength {
+			return "", 0, fmt.Errorf("compressed or invalid label")
+		}
+		if offset+length > len(data) {
+			return "", 0, fmt.Errorf("truncated name")
+		}
+		writeLabel(&name, data[offset:offset+length])
+		offset += length
+	}
+	if name.Len() == 0 {
+		return ".", offset, nil
+	}
+	return name.String(), offset, nil
+}
+
+// writeLabel writes a label of a domain name, followed by a dot, in
+// presentation format. Bytes that are special within zone files are escaped.
+func writeLabel(name *strings.Builder, label []byte) {
+	for _, b := range label {
+		switch {
+		case b == '.' || b == escapeByte || b == quoteByte || b == '(' || b == ')' || b == ';' || b == '@' || b == '$':
+			name.WriteByte(escapeByte)
+			name.WriteByte(b)
+		case b < 0x21 || b > 0x7e:
+			name.WriteString(fmt.Sprintf("\\%03d", b))
+		default:
+			name.WriteByte(b)
+		}
+	}
+	name.WriteByte('.')
+}
+
+func packUint(buf []byte, encoding wireEncoding, value string) ([]byte, error) {
+	bits := map[wireEncoding]int{wireUint8: 8, wireUint16: 16, wireUint32: 32}[encoding]
+	number, err := strconv.ParseUint(value, 10, bits)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a %d bit number", value, bits)
+	}
+	switch encoding {
+	case wireUint8:
+		return append(buf, byte(number)), nil
+	case wireUint16:
+		return binary.BigEndian.AppendUint16(buf, uint16(number)), nil
+	}
+	return binary.BigEndian.AppendUint32(buf, uint32(number)), nil
+}
+
+func unpackUint(data []byte, offset int, encoding wireEncoding) (string, int, error) {
+	size := map[wireEncoding]int{wireUint8: 1, wireUint16: 2, wireUint32: 4}[encoding]
+	if offset+size > len(data) {
+		return "", 0, fmt.Errorf("truncated number")
+	}
+	var number uint32
+	switch encoding {
+	case wireUint8:
+		number = uint32(data[offset])
+	case wireUint16:
+		number = uint32(binary.BigEndian.Uint16(data[offset:]))
+	default:
+		number = binary.BigEndian.Uint32(data[offset:])
+	}
+	return strconv.FormatUint(uint64(number), 10), offset + size, nil
+}
+
+// packCharacterString appends a <character-string> token in presentation
+// format as a length prefixed string.
+func packCharacterString(buf []byte, token string) ([]byte, error) {
+	str, err := parseCharacterString(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(str) > maxCharacterStringLength {
+		return nil, fmt.Errorf("character string exceeds %d bytes", maxCharacterStringLength)
+	}
+	buf = append(buf, byte(len(str)))
+	return append(buf, str...), nil
+}
+
+func unpackCharacterString(data []byte, offset int) (string, int, error) {
+	if offset >= len(data) || offset+1+int(data[offset]) > len(data) {
+		return "", 0, fmt.Errorf("truncated character string")
+	}
+	end := offset + 1 + int(data[offset])
+	return quoteCharacterString(string(data[offset+1 : end])), end, nil
+}
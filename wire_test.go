@@ -0,0 +1,89 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_PackRdata(t *testing.T) {
+	tests := []struct {
+		rr       ResourceRecord
+		expected []byte
+	}{
+		{
+			rr:       ResourceRecord{Type: "A", Values: []string{"192.0.2.1"}},
+			expected: []byte{192, 0, 2, 1},
+		},
+		{
+			rr:       ResourceRecord{Type: "MX", Values: []string{"10", "mx.example."}},
+			expected: []byte{0, 10, 2, 'm', 'x', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 0},
+		},
+		{
+			rr:       ResourceRecord{Type: "TXT", Values: []string{`"a b"`, "c"}},
+			expected: []byte{3, 'a', ' ', 'b', 1, 'c'},
+		},
+		{
+			rr:       ResourceRecord{Type: "CAA", Values: []string{"0", "issue", `"ca.example"`}},
+			expected: []byte{0, 5, 'i', 's', 's', 'u', 'e', 'c', 'a', '.', 'e', 'x', 'a', 'm', 'p', 'l', 'e'},
+		},
+		{
+			rr:       ResourceRecord{Type: "DS", Values: []string{"60485", "5", "1", "2BB183AF5F22588179A5", "3B0A98631FAD1A292118"}},
+			expected: []byte{0xec, 0x45, 5, 1, 0x2b, 0xb1, 0x83, 0xaf, 0x5f, 0x22, 0x58, 0x81, 0x79, 0xa5, 0x3b, 0x0a, 0x98, 0x63, 0x1f, 0xad, 0x1a, 0x29, 0x21, 0x18},
+		},
+		{
+			rr:       ResourceRecord{Type: "PTR", Values: []string{`a\.b.c\046.`}},
+			expected: []byte{3, 'a', '.', 'b', 2, 'c', '.', 0},
+		},
+		{
+			rr:       ResourceRecord{Type: "TYPE65280", Values: []string{`\#`, "2", "ABCD"}},
+			expected: []byte{0xab, 0xcd},
+		},
+	}
+
+	for _, test := range tests {
+		found, err := packRdata(test.rr)
+		require.Nil(t, err, test.rr.Type)
+		assert.Equal(t, test.expected, found, test.rr.Type)
+
+		values, err := unpackRdata(test.rr.Type, found)
+		require.Nil(t, err, test.rr.Type)
+		repacked, err := packRdata(ResourceRecord{Type: test.rr.Type, Values: values})
+		require.Nil(t, err, test.rr.Type)
+		assert.Equal(t, found, repacked, test.rr.Type)
+	}
+}
+
+func Test_PackRdata_Errors(t *testing.T) {
+	_, err := packRdata(ResourceRecord{Type: "MX", Values: []string{"10", "mx"}})
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+	assert.ErrorContains(t, err, "mx is not an absolute name")
+
+	_, err = packRdata(ResourceRecord{Type: "A", Values: []string{"2001:db8::1"}})
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+
+	_, err = packRdata(ResourceRecord{Type: "MX", Values: []string{"65536", "mx."}})
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+
+	_, err = packRdata(ResourceRecord{Type: "NSEC", Values: []string{"host.example.", "A", "MX"}})
+	assert.ErrorIs(t, err, ErrUnsupportedRecord)
+
+	_, err = packRdata(ResourceRecord{Type: "TYPE65280", Values: []string{`\#`, "3", "ABCD"}})
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+}
+
+func Test_UnpackRdata(t *testing.T) {
+	found, err := unpackRdata("NSEC", []byte{0, 0, 6, 0x40})
+	require.Nil(t, err)
+	assert.Equal(t, []string{`\#`, "4", "00000640"}, found)
+
+	found, err = unpackRdata("TXT", []byte{})
+	require.Nil(t, err)
+	assert.Empty(t, found)
+
+	_, err = unpackRdata("A", []byte{192, 0, 2})
+	assert.ErrorIs(t, err, ErrInvalidRdata)
+
+	_, err = unpackRdata("A", []byte{192, 0, 2, 1, 0})
+	assert.ErrorContains(t, err, "1 trailing bytes")
+}
@@ -5,6 +5,11 @@ import "strings"
 type Zone struct {
 	SOA     ResourceRecord
 	Records []ResourceRecord
+
+	// Comments holds the comment lines of the source that do not belong to
+	// a record, without their leading `;`. It is nil unless the parser was
+	// created with [WithComments].
+	Comments []string
 }
 
 func (z *Zone) String() string {
@@ -18,3 +23,14 @@ func (z *Zone) String() string {
 	}
 	return str.String()
 }
+
+// AllRecords lists the SOA record of the zone, if present, followed by all
+// other records. The returned slice is a new slice, but the records share
+// their values with the zone.
+func (z *Zone) AllRecords() []ResourceRecord {
+	records := make([]ResourceRecord, 0, len(z.Records)+1)
+	if z.SOA.IsEmpty() == false {
+		records = append(records, z.SOA)
+	}
+	return append(records, z.Records...)
+}
@@ -0,0 +1,22 @@
+package zone
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_Zone_AllRecords(t *testing.T) {
+	z := mustParse(t, mutationZone)
+	records := z.AllRecords()
+	assert.Len(t, records, len(z.Records)+1)
+	assert.Equal(t, z.SOA, records[0])
+	assert.Equal(t, z.Records, records[1:])
+
+	// The zone is not changed by changes of the slice.
+	records[1] = ResourceRecord{}
+	assert.Equal(t, "example.com.", z.Records[0].Name)
+
+	z.SOA = ResourceRecord{}
+	assert.Equal(t, z.Records, z.AllRecords())
+	assert.Equal(t, []ResourceRecord{}, (&Zone{}).AllRecords())
+}